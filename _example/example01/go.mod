@@ -1,6 +1,6 @@
 module example01
 
-go 1.20
+go 1.21
 
 require github.com/appleboy/graceful v0.0.0-20220102100755-188ad806f508
 
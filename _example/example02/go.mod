@@ -1,6 +1,6 @@
 module example02
 
-go 1.20
+go 1.21
 
 require github.com/appleboy/graceful v0.0.2-0.20220102112459-6e92f1bc460a
 
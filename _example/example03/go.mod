@@ -1,6 +1,6 @@
 module example03
 
-go 1.20
+go 1.21
 
 require (
 	github.com/appleboy/graceful v0.0.2-0.20220102161147-760cecbcf493
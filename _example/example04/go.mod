@@ -0,0 +1,7 @@
+module example04
+
+go 1.21
+
+require github.com/appleboy/graceful v0.0.0
+
+replace github.com/appleboy/graceful => ../../
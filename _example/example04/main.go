@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/appleboy/graceful"
+)
+
+// spotInterruptionURL answers 404 until AWS schedules the interruption
+// of the spot instance, about two minutes before it happens.
+const spotInterruptionURL = "http://169.254.169.254/latest/meta-data/spot/instance-action"
+
+// watchSpotInterruption returns nil once the interruption notice shows up.
+// Instances requiring IMDSv2 also need a session token header.
+func watchSpotInterruption(ctx context.Context) error {
+	client := &http.Client{Timeout: 2 * time.Second}
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, spotInterruptionURL, nil)
+		if err != nil {
+			return err
+		}
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				log.Println("spot interruption notice received")
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func main() {
+	m := graceful.NewManager()
+
+	// drain as soon as the interruption is announced, ahead of SIGTERM
+	m.TriggerFrom(context.Background(), watchSpotInterruption)
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+				log.Println("working job")
+				time.Sleep(1 * time.Second)
+			}
+		}
+	})
+
+	m.AddShutdownJob(func() error {
+		log.Println("shutdown job")
+		return nil
+	})
+
+	<-m.Done()
+}
@@ -0,0 +1,114 @@
+package graceful
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// Action is what the manager does when it receives a signal.
+// The zero value is ActionIgnore, so a signal without an action never
+// shuts down the process.
+type Action int
+
+const (
+	// ActionIgnore only logs the signal.
+	ActionIgnore Action = iota
+	// ActionShutdown starts the graceful shutdown.
+	ActionShutdown
+	// ActionReload runs the jobs added with AddReloadJob.
+	ActionReload
+	// ActionStackDump writes the stacks of all goroutines to the error logger.
+	ActionStackDump
+	// ActionPause pauses the jobs waiting in WaitResumed, see Pause.
+	ActionPause
+	// ActionResume resumes the paused jobs, see Resume.
+	ActionResume
+)
+
+// String returns the action name
+func (a Action) String() string {
+	switch a {
+	case ActionIgnore:
+		return "ignore"
+	case ActionShutdown:
+		return "shutdown"
+	case ActionReload:
+		return "reload"
+	case ActionStackDump:
+		return "stack dump"
+	case ActionPause:
+		return "pause"
+	case ActionResume:
+		return "resume"
+	}
+	return "unknown"
+}
+
+// defaultSignalActions shuts down on SIGINT and SIGTERM, pauses on SIGTSTP
+// and resumes on SIGCONT, and only logs the other handled signals.
+func defaultSignalActions() map[os.Signal]Action {
+	actions := make(map[os.Signal]Action, len(signals))
+	for _, sig := range signals {
+		switch sig {
+		case syscall.SIGINT, syscall.SIGTERM:
+			actions[sig] = ActionShutdown
+		case pauseSignal:
+			actions[sig] = ActionPause
+		case resumeSignal:
+			actions[sig] = ActionResume
+		default:
+			actions[sig] = ActionIgnore
+		}
+	}
+	return actions
+}
+
+// signalName returns the SIGINT style name for the common signals
+func signalName(sig os.Signal) string {
+	switch sig {
+	case syscall.SIGINT:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	}
+	return sig.String()
+}
+
+// defaultSignalLog is the line logged when a signal starts the shutdown
+func defaultSignalLog(sig os.Signal, id string) string {
+	return fmt.Sprintf("PID %s. Received %s. Shutting down...", id, signalName(sig))
+}
+
+// processID returns the process identity logged with signals,
+// see WithPIDFunc
+func (g *Manager) processID() string {
+	if g.pidFunc != nil {
+		return g.pidFunc()
+	}
+	return strconv.Itoa(syscall.Getpid())
+}
+
+// AddReloadJob add task that runs every time a signal mapped to
+// ActionReload is received. Reload jobs run one after another and
+// their errors are logged.
+func (g *Manager) AddReloadJob(f func() error) {
+	g.lock.Lock()
+	g.reloadJobs = append(g.reloadJobs, f)
+	g.lock.Unlock()
+}
+
+// reload runs every reload job
+func (g *Manager) reload() {
+	g.lock.RLock()
+	jobs := make([]func() error, len(g.reloadJobs))
+	copy(jobs, g.reloadJobs)
+	g.lock.RUnlock()
+
+	for _, f := range jobs {
+		if err := f(); err != nil {
+			g.log().Errorf("reload job failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package graceful
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func sendSignal(t *testing.T, sig os.Signal) {
+	t.Helper()
+	process, err := os.FindProcess(syscall.Getpid())
+	if err != nil {
+		t.Fatalf("os.FindProcess error: %v", err)
+	}
+	if err := process.Signal(sig); err != nil {
+		t.Fatalf("process.Signal error: %v", err)
+	}
+}
+
+func TestSignalActionReload(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithSignalAction(syscall.SIGHUP, ActionReload),
+	)
+	reloaded := make(chan struct{})
+	m.AddReloadJob(func() error {
+		close(reloaded)
+		return nil
+	})
+	<-m.Started()
+
+	sendSignal(t, syscall.SIGHUP)
+
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("reload job did not run")
+	}
+	select {
+	case <-m.ShutdownContext().Done():
+		t.Error("reload should not shut down")
+	default:
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestSignalActionStackDump(t *testing.T) {
+	setup()
+	l := NewBufferLogger(10)
+	m := NewManager(
+		WithLogger(l),
+		WithSignalAction(syscall.SIGQUIT, ActionStackDump),
+		WithSignalAction(syscall.SIGINT, ActionIgnore),
+	)
+	<-m.Started()
+
+	sendSignal(t, syscall.SIGQUIT)
+	sendSignal(t, syscall.SIGINT)
+	time.Sleep(50 * time.Millisecond)
+
+	if m.ShutdownCause() != nil {
+		t.Errorf("shutdown should not start: %v", m.ShutdownCause())
+	}
+	dumped := false
+	for _, msg := range l.Messages() {
+		if strings.HasPrefix(msg, "ERROR: goroutine stacks:") {
+			dumped = true
+		}
+	}
+	if !dumped {
+		t.Errorf("missing stack dump: %v", l.Messages())
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestActionString(t *testing.T) {
+	if ActionReload.String() != "reload" || Action(99).String() != "unknown" {
+		t.Error("action string error")
+	}
+}
+
+func TestActionZeroValue(t *testing.T) {
+	var a Action
+	if a != ActionIgnore {
+		t.Errorf("zero action error: %v", a)
+	}
+}
+
+func TestWithSignalLogFormat(t *testing.T) {
+	setup()
+	l := NewBufferLogger(10)
+	m := NewManager(
+		WithLogger(l),
+		WithSignalLogFormat(func(sig os.Signal, pid int) string {
+			return "shutdown signal=" + signalName(sig)
+		}),
+	)
+	<-m.Started()
+
+	sendSignal(t, syscall.SIGTERM)
+	<-m.Done()
+
+	if msgs := l.Messages(); len(msgs) == 0 || msgs[0] != "INFO: shutdown signal=SIGTERM" {
+		t.Errorf("messages error: %v", msgs)
+	}
+}
+
+func TestDefaultSignalLog(t *testing.T) {
+	if s := defaultSignalLog(syscall.SIGINT, "42"); s != "PID 42. Received SIGINT. Shutting down..." {
+		t.Errorf("default signal log error: %s", s)
+	}
+}
+
+func TestWithPIDFunc(t *testing.T) {
+	setup()
+	l := NewBufferLogger(10)
+	m := NewManager(
+		WithLogger(l),
+		WithPIDFunc(func() string {
+			return "container-1"
+		}),
+	)
+	<-m.Started()
+
+	sendSignal(t, syscall.SIGTERM)
+	<-m.Done()
+
+	if msgs := l.Messages(); len(msgs) == 0 || msgs[0] != "INFO: PID container-1. Received SIGTERM. Shutting down..." {
+		t.Errorf("messages error: %v", msgs)
+	}
+}
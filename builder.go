@@ -0,0 +1,69 @@
+package graceful
+
+import "sync"
+
+// Builder buffers job registrations until the manager is created,
+// so jobs can be declared before NewManager is called.
+type Builder struct {
+	lock         sync.Mutex
+	runningJobs  []func(*Manager)
+	shutdownJobs []func(*Manager)
+}
+
+// NewBuilder creates an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// AddRunningJob buffers a running task
+func (b *Builder) AddRunningJob(f RunningJob) {
+	b.addRunning(func(m *Manager) { m.AddRunningJob(f) })
+}
+
+// AddNamedRunningJob buffers a named running task
+func (b *Builder) AddNamedRunningJob(name string, f RunningJob) {
+	b.addRunning(func(m *Manager) { m.AddNamedRunningJob(name, f) })
+}
+
+// AddShutdownJob buffers a shutdown task
+func (b *Builder) AddShutdownJob(f ShtdownJob) {
+	b.addShutdown(func(m *Manager) { m.AddShutdownJob(f) })
+}
+
+// AddNamedShutdownJob buffers a named shutdown task
+func (b *Builder) AddNamedShutdownJob(name string, f ShtdownJob) {
+	b.addShutdown(func(m *Manager) { m.AddNamedShutdownJob(name, f) })
+}
+
+func (b *Builder) addRunning(register func(*Manager)) {
+	b.lock.Lock()
+	b.runningJobs = append(b.runningJobs, register)
+	b.lock.Unlock()
+}
+
+func (b *Builder) addShutdown(register func(*Manager)) {
+	b.lock.Lock()
+	b.shutdownJobs = append(b.shutdownJobs, register)
+	b.lock.Unlock()
+}
+
+// Build creates the manager with the given options and registers the
+// buffered jobs in it. Running jobs start right away. The buffer is emptied,
+// so calling Build again only registers jobs added in the meantime.
+func (b *Builder) Build(opts ...Option) *Manager {
+	b.lock.Lock()
+	shutdownJobs, runningJobs := b.shutdownJobs, b.runningJobs
+	b.shutdownJobs, b.runningJobs = nil, nil
+	b.lock.Unlock()
+
+	m := NewManager(opts...)
+	// shutdown jobs first so they are known before any running job can fail
+	for _, register := range shutdownJobs {
+		register(m)
+	}
+	for _, register := range runningJobs {
+		register(m)
+	}
+
+	return m
+}
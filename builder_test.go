@@ -0,0 +1,35 @@
+package graceful
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	setup()
+	var count int32 = 0
+	b := NewBuilder()
+
+	started := make(chan struct{})
+	b.AddRunningJob(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+	b.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	m := b.Build(WithLogger(NewEmptyLogger()))
+	<-started
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&count) != 2 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+}
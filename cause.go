@@ -0,0 +1,30 @@
+package graceful
+
+import (
+	"errors"
+	"os"
+)
+
+var (
+	// ErrShutdownRequested is the cause when DoGracefulShutdown is called
+	ErrShutdownRequested = errors.New("graceful: shutdown requested")
+	// ErrParentContextDone is the cause when the background context is closed
+	ErrParentContextDone = errors.New("graceful: background context closed")
+	// ErrTriggerChannel is the cause when the trigger channel fired
+	ErrTriggerChannel = errors.New("graceful: trigger channel fired")
+	// ErrDrainRequested is the cause when Drain is called
+	ErrDrainRequested = errors.New("graceful: drain requested")
+	// ErrShutdownAfter is the cause when the ShutdownAfter delay elapsed
+	ErrShutdownAfter = errors.New("graceful: shutdown delay elapsed")
+	// ErrExternalTrigger is the cause when a TriggerFrom watcher returned
+	ErrExternalTrigger = errors.New("graceful: external trigger fired")
+)
+
+// SignalError is the cause when an OS signal started the shutdown
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return "graceful: received signal " + e.Signal.String()
+}
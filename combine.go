@@ -0,0 +1,73 @@
+package graceful
+
+import "errors"
+
+// CombinedManager coordinates the shutdown of several managers, see
+// CombineManagers.
+type CombinedManager struct {
+	managers []*Manager
+	done     chan struct{}
+}
+
+// CombineManagers returns a CombinedManager whose shutdown fans out to every
+// manager: as soon as one of them starts shutting down, for a signal or any
+// other cause, the others are shut down too. Done is closed once all of them
+// completed. A manager given several times is only combined once. Build the
+// managers with NewIndependentManager, NewManager always returns the same one.
+func CombineManagers(managers ...*Manager) *CombinedManager {
+	c := &CombinedManager{done: make(chan struct{})}
+	seen := make(map[*Manager]bool, len(managers))
+	for _, m := range managers {
+		if m == nil || seen[m] {
+			continue
+		}
+		seen[m] = true
+		c.managers = append(c.managers, m)
+	}
+
+	for _, m := range c.managers {
+		go func(m *Manager) {
+			select {
+			case <-m.ShutdownContext().Done():
+				c.DoGracefulShutdown()
+			case <-c.done:
+			}
+		}(m)
+	}
+	go func() {
+		for _, m := range c.managers {
+			<-m.Done()
+		}
+		close(c.done)
+	}()
+	return c
+}
+
+// DoGracefulShutdown starts the shutdown of every combined manager.
+func (c *CombinedManager) DoGracefulShutdown() {
+	for _, m := range c.managers {
+		m.DoGracefulShutdown()
+	}
+}
+
+// Done is closed once every combined manager completed its shutdown.
+func (c *CombinedManager) Done() <-chan struct{} {
+	return c.done
+}
+
+// Errors returns the errors of every combined manager, in the order the
+// managers were given.
+func (c *CombinedManager) Errors() []error {
+	var errs []error
+	for _, m := range c.managers {
+		errs = append(errs, m.Errors()...)
+	}
+	return errs
+}
+
+// Run blocks until Done is closed and returns the errors of every combined
+// manager joined with errors.Join, or nil.
+func (c *CombinedManager) Run() error {
+	<-c.done
+	return errors.Join(c.Errors()...)
+}
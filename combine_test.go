@@ -0,0 +1,42 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCombineManagers(t *testing.T) {
+	m1 := NewIndependentManager(WithLogger(NewEmptyLogger()), WithoutSignalHandler())
+	m2 := NewIndependentManager(WithLogger(NewEmptyLogger()), WithoutSignalHandler())
+
+	errFlush := errors.New("flush failed")
+	m2.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	m2.AddShutdownJob(func() error {
+		return errFlush
+	})
+
+	c := CombineManagers(m1, m2, m1)
+	if len(c.managers) != 2 {
+		t.Fatalf("managers count error: %d", len(c.managers))
+	}
+
+	// shutting down one manager shuts down the other
+	m1.DoGracefulShutdown()
+	<-c.Done()
+
+	select {
+	case <-m2.Done():
+	default:
+		t.Error("second manager should be done")
+	}
+	if err := c.Run(); !errors.Is(err, errFlush) {
+		t.Errorf("run error: %v", err)
+	}
+	if len(c.Errors()) != 1 {
+		t.Errorf("errors count error: %d", len(c.Errors()))
+	}
+}
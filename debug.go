@@ -0,0 +1,132 @@
+package graceful
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DebugOption configures the debug server.
+type DebugOption func(*debugOptions)
+
+type debugOptions struct {
+	token string
+}
+
+// WithDebugToken enables POST /graceful/shutdown and /graceful/restart for
+// requests sending "Authorization: Bearer <token>". Without a token these
+// endpoints are disabled.
+func WithDebugToken(token string) DebugOption {
+	return func(o *debugOptions) {
+		o.token = token
+	}
+}
+
+// ServeDebug starts an HTTP server on addr for shutdown introspection:
+//
+//	GET  /graceful/stats     Stats as JSON
+//	GET  /graceful/pending   names of the unfinished jobs as JSON
+//	POST /graceful/shutdown  starts the graceful shutdown
+//	POST /graceful/restart   restarts the job named by the job query parameter
+//
+// The server keeps answering during the shutdown and is closed by a
+// shutdown job in the last phase. It returns the error of listening on addr.
+func (g *Manager) ServeDebug(addr string, opts ...DebugOption) error {
+	o := &debugOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           g.debugHandler(o),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	g.AddNamedRunningJob("debug-server", func(ctx context.Context) error {
+		go func() {
+			if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				g.log().Errorf("debug server: %v", err)
+			}
+		}()
+		<-ctx.Done()
+		return nil
+	})
+	g.addShutdownJob(shutdownJob{
+		name:  "debug-server-close",
+		phase: math.MaxInt,
+		fn: func() error {
+			return srv.Close()
+		},
+	})
+	return nil
+}
+
+// debugHandler serves the debug endpoints
+func (g *Manager) debugHandler(o *debugOptions) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graceful/stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, g.Stats())
+	})
+	mux.HandleFunc("/graceful/pending", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, g.PendingJobs())
+	})
+	mux.HandleFunc("/graceful/shutdown", func(w http.ResponseWriter, r *http.Request) {
+		if !authorize(w, r, o) {
+			return
+		}
+		g.log().Info("Shutdown requested through the debug server")
+		g.doGracefulShutdown()
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/graceful/restart", func(w http.ResponseWriter, r *http.Request) {
+		if !authorize(w, r, o) {
+			return
+		}
+		err := g.RestartJob(r.URL.Query().Get("job"))
+		switch {
+		case err == nil:
+			w.WriteHeader(http.StatusAccepted)
+		case errors.Is(err, ErrUnknownJob):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusConflict)
+		}
+	})
+	return mux
+}
+
+// authorize checks the request is a POST carrying the debug token,
+// and writes the error response otherwise
+func authorize(w http.ResponseWriter, r *http.Request, o *debugOptions) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if o.token == "" {
+		http.Error(w, "endpoint disabled", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(o.token)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
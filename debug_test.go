@@ -0,0 +1,80 @@
+package graceful
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	h := m.debugHandler(&debugOptions{token: "secret"})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graceful/stats", nil))
+	var s Stats
+	if err := json.Unmarshal(w.Body.Bytes(), &s); err != nil || s.ShuttingDown {
+		t.Errorf("stats error: %v %s", err, w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graceful/shutdown", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status error: %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/graceful/shutdown", nil)
+	r.Header.Set("Authorization", "Bearer wrong")
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized || m.ShutdownCause() != nil {
+		t.Errorf("wrong token should be rejected: %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/graceful/shutdown", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("status error: %d", w.Code)
+	}
+	<-m.Done()
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graceful/pending", nil))
+	if w.Body.String() != "[]\n" {
+		t.Errorf("pending error: %s", w.Body)
+	}
+}
+
+func TestDebugHandlerWithoutToken(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	h := m.debugHandler(&debugOptions{})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graceful/shutdown", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status error: %d", w.Code)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestServeDebug(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	if err := m.ServeDebug("127.0.0.1:0"); err != nil {
+		t.Fatalf("serve debug error: %v", err)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if len(m.Errors()) != 0 || len(m.PendingJobs()) != 0 {
+		t.Errorf("debug server should stop cleanly: %v %v", m.Errors(), m.PendingJobs())
+	}
+}
@@ -0,0 +1,30 @@
+package graceful
+
+// DedupPolicy is what WithDedupShutdownJobs does with a duplicate shutdown job.
+type DedupPolicy int
+
+const (
+	// DedupIgnore keeps the first job and drops the duplicate.
+	DedupIgnore DedupPolicy = iota
+	// DedupReplace keeps the position of the first job but runs the duplicate instead.
+	DedupReplace
+)
+
+// dedupShutdownJob applies the dedup policy if a shutdown job named like job
+// is already registered, and reports whether it did. The caller must hold the lock.
+func (g *Manager) dedupShutdownJob(job shutdownJob) bool {
+	for i := range g.runAtShutdown {
+		if g.runAtShutdown[i].name != job.name {
+			continue
+		}
+		if g.dedupPolicy == DedupReplace {
+			g.logger.Infof("shutdown job %q registered again, replacing it", job.name)
+			g.runAtShutdown[i].fn = job.fn
+			g.runAtShutdown[i].phase = job.phase
+		} else {
+			g.logger.Infof("shutdown job %q registered again, ignoring the duplicate", job.name)
+		}
+		return true
+	}
+	return false
+}
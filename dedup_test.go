@@ -0,0 +1,38 @@
+package graceful
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestDedupShutdownJobs(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy DedupPolicy
+		want   int32
+	}{
+		{"ignore", DedupIgnore, 1},
+		{"replace", DedupReplace, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setup()
+			var got int32 = 0
+			m := NewManager(WithLogger(NewEmptyLogger()), WithDedupShutdownJobs(tc.policy))
+
+			m.AddNamedShutdownJob("metrics", func() error {
+				atomic.AddInt32(&got, 1)
+				return nil
+			})
+			m.AddNamedShutdownJob("metrics", func() error {
+				atomic.AddInt32(&got, 2)
+				return nil
+			})
+			m.DoGracefulShutdown()
+			<-m.Done()
+
+			if atomic.LoadInt32(&got) != tc.want {
+				t.Errorf("got %d, want %d", atomic.LoadInt32(&got), tc.want)
+			}
+		})
+	}
+}
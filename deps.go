@@ -0,0 +1,111 @@
+package graceful
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDependencyCycle is returned when a job would depend on itself,
+// directly or through other jobs
+var ErrDependencyCycle = errors.New("graceful: dependency cycle")
+
+// AddRunningJobAfter add running task that starts once every job named in
+// deps called ready or returned, see AddNamedRunningJobAfter.
+func (g *Manager) AddRunningJobAfter(deps []string, f RunningJob) error {
+	return g.AddNamedRunningJobAfter(g.nextRunningJobName(), deps, f)
+}
+
+// AddNamedRunningJobAfter add named running task that starts once every job
+// named in deps called ready or returned. Plain running jobs count as ready
+// when they return nil, jobs added with AddRunningJobReady when they call
+// ready or return nil. A dependency returning an error or panicking first
+// drops the job, it is logged and never runs. deps may name jobs that are
+// added later. It returns an error wrapping ErrDependencyCycle, and does not
+// add the job, when the job would wait for itself. A job still waiting when
+// the shutdown starts never runs.
+func (g *Manager) AddNamedRunningJobAfter(name string, deps []string, f RunningJob) error {
+	g.lock.Lock()
+	if path := g.dependencyPath(deps, name, []string{name}); path != nil {
+		g.lock.Unlock()
+		return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(path, " -> "))
+	}
+	g.jobDeps[name] = append([]string(nil), deps...)
+	gates := make([]chan struct{}, 0, len(deps))
+	for _, dep := range deps {
+		gates = append(gates, g.readyGate(dep))
+	}
+	g.lock.Unlock()
+
+	g.runningWaitGroup.Run(func() {
+		for i, gate := range gates {
+			select {
+			case <-gate:
+			case <-g.shutdownCtx.Done():
+				g.log().Infof("job %q not started: shutdown began before its dependencies were ready", name)
+				return
+			}
+			g.lock.RLock()
+			err := g.depErrors[deps[i]]
+			g.lock.RUnlock()
+			if err != nil {
+				g.log().Errorf("job %q not started: dependency %q failed: %v", name, deps[i], err)
+				return
+			}
+		}
+		g.AddNamedRunningJob(name, f)
+	})
+	return nil
+}
+
+// dependencyPath returns the path from path's last job through deps to
+// target, or nil if target cannot be reached. Caller must hold the lock.
+func (g *Manager) dependencyPath(deps []string, target string, path []string) []string {
+	for _, dep := range deps {
+		next := append(path[:len(path):len(path)], dep)
+		if dep == target {
+			return next
+		}
+		if found := g.dependencyPath(g.jobDeps[dep], target, next); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// readyGate returns the channel closed when the named job is ready.
+// Caller must hold the lock.
+func (g *Manager) readyGate(name string) chan struct{} {
+	gate, ok := g.readyGates[name]
+	if !ok {
+		gate = make(chan struct{})
+		g.readyGates[name] = gate
+	}
+	return gate
+}
+
+// markReady releases the jobs waiting for the named job
+func (g *Manager) markReady(name string) {
+	g.releaseGate(name, nil)
+}
+
+// markFailed releases the jobs waiting for the named job with err,
+// so they are dropped instead of started. It does nothing once the job
+// is ready.
+func (g *Manager) markFailed(name string, err error) {
+	g.releaseGate(name, err)
+}
+
+func (g *Manager) releaseGate(name string, err error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	gate := g.readyGate(name)
+	select {
+	case <-gate:
+	default:
+		if err != nil {
+			g.depErrors[name] = err
+		}
+		close(gate)
+	}
+}
@@ -0,0 +1,152 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAddRunningJobAfter(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	var migrated int32
+	started := make(chan bool, 1)
+	// the dependent is added first to check it waits for a job added later
+	err := m.AddNamedRunningJobAfter("http", []string{"migrate"}, func(ctx context.Context) error {
+		started <- atomic.LoadInt32(&migrated) == 1
+		<-ctx.Done()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+
+	m.AddNamedRunningJob("migrate", func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		atomic.StoreInt32(&migrated, 1)
+		return nil
+	})
+
+	select {
+	case ok := <-started:
+		if !ok {
+			t.Error("job started before its dependency returned")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job never started")
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestAddRunningJobAfterReady(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	var ready int32
+	m.AddNamedRunningJobReady("db", func(ctx context.Context, markReady func()) error {
+		atomic.StoreInt32(&ready, 1)
+		markReady()
+		<-ctx.Done()
+		return nil
+	})
+
+	started := make(chan bool, 1)
+	_ = m.AddRunningJobAfter([]string{"db"}, func(ctx context.Context) error {
+		started <- atomic.LoadInt32(&ready) == 1
+		return nil
+	})
+
+	select {
+	case ok := <-started:
+		if !ok {
+			t.Error("job started before its dependency was ready")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job never started")
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestAddRunningJobAfterShutdown(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	var ran int32
+	_ = m.AddNamedRunningJobAfter("http", []string{"missing"}, func(ctx context.Context) error {
+		atomic.StoreInt32(&ran, 1)
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&ran) != 0 {
+		t.Error("job should not run once the shutdown started")
+	}
+}
+
+func TestAddRunningJobAfterFailed(t *testing.T) {
+	setup()
+	logger := NewBufferLogger(50)
+	m := NewManager(WithLogger(logger))
+
+	m.AddNamedRunningJob("migrate", func(ctx context.Context) error {
+		return errors.New("migration failed")
+	})
+
+	var ran int32
+	_ = m.AddNamedRunningJobAfter("http", []string{"migrate"}, func(ctx context.Context) error {
+		atomic.StoreInt32(&ran, 1)
+		return nil
+	})
+
+	const dropped = `dependency "migrate" failed`
+	deadline := time.Now().Add(time.Second)
+	for !logger.Contains(dropped) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !logger.Contains(dropped) {
+		t.Errorf("messages error: %v", logger.Messages())
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&ran) != 0 {
+		t.Error("job should not run when its dependency failed")
+	}
+}
+
+func TestAddRunningJobAfterCycle(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	noop := func(ctx context.Context) error { return nil }
+	if err := m.AddNamedRunningJobAfter("a", []string{"b"}, noop); err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	if err := m.AddNamedRunningJobAfter("b", []string{"c"}, noop); err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+
+	err := m.AddNamedRunningJobAfter("c", []string{"a"}, noop)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("cycle error: %v", err)
+	}
+	if err.Error() != "graceful: dependency cycle: c -> a -> b -> c" {
+		t.Errorf("cycle message error: %v", err)
+	}
+	if err := m.AddNamedRunningJobAfter("d", []string{"d"}, noop); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("self dependency error: %v", err)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
@@ -0,0 +1,106 @@
+package graceful
+
+import (
+	"errors"
+	"time"
+)
+
+// state is the lifecycle state of the manager
+type state int
+
+const (
+	// stateRunning accepts new running jobs
+	stateRunning state = iota
+	// stateDraining cancelled the running jobs but did not run shutdown jobs yet
+	stateDraining
+	// stateShuttingDown runs the shutdown jobs and waits for all jobs to return
+	stateShuttingDown
+)
+
+var (
+	// ErrNotRunning is returned by Drain when the manager already left the running state
+	ErrNotRunning = errors.New("graceful: manager is not running")
+	// ErrNotDraining is returned by Finish when Drain was not called first
+	ErrNotDraining = errors.New("graceful: manager is not draining")
+)
+
+// Drain is the first step of a two step shutdown. It cancels the shutdown
+// context so running jobs wind down and no new running job is accepted,
+// but neither runs the shutdown jobs nor closes Done(). Call Finish to
+// complete the shutdown.
+func (g *Manager) Drain() error {
+	g.lock.Lock()
+	if g.state != stateRunning {
+		g.lock.Unlock()
+		return ErrNotRunning
+	}
+	g.state = stateDraining
+	g.lock.Unlock()
+	g.removeReadyFile()
+
+	g.log().Info("Draining running jobs...")
+	g.shutdownCtxCancel(ErrDrainRequested)
+	return nil
+}
+
+// Finish is the second step of a two step shutdown started by Drain.
+// It runs the shutdown jobs and closes Done() once every job returned.
+func (g *Manager) Finish() error {
+	g.lock.RLock()
+	draining := g.state == stateDraining
+	g.lock.RUnlock()
+	if !draining {
+		return ErrNotDraining
+	}
+
+	g.shutdownWithCause(ErrDrainRequested)
+	return nil
+}
+
+// AddDrainWaiter add predicate reporting whether a resource finished draining,
+// e.g. a queue consumer with no in-flight message left. Once the shutdown
+// starts, the shutdown jobs wait until every predicate returns true, polled
+// every WithDrainPollInterval, or until the shutdown deadline passes.
+func (g *Manager) AddDrainWaiter(drained func() bool) {
+	g.lock.Lock()
+	g.drainWaiters = append(g.drainWaiters, drained)
+	g.lock.Unlock()
+}
+
+// waitDrainWaiters polls the drain waiters until they all report drained
+func (g *Manager) waitDrainWaiters() {
+	g.lock.RLock()
+	waiters := make([]func() bool, len(g.drainWaiters))
+	copy(waiters, g.drainWaiters)
+	g.lock.RUnlock()
+
+	last := -1
+	for {
+		pending := waiters[:0:0]
+		for _, drained := range waiters {
+			if !drained() {
+				pending = append(pending, drained)
+			}
+		}
+		waiters = pending
+		if len(waiters) == 0 {
+			return
+		}
+		if len(waiters) != last {
+			g.log().Infof("waiting for %d drain waiters", len(waiters))
+			last = len(waiters)
+		}
+
+		wait := g.drainPollInterval
+		if remaining, ok := g.shutdownRemaining(); ok {
+			if remaining <= 0 {
+				g.log().Errorf("shutdown deadline passed with %d drain waiters pending", len(waiters))
+				return
+			}
+			if remaining < wait {
+				wait = remaining
+			}
+		}
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,133 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDrainAndFinish(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	if err := m.Finish(); !errors.Is(err, ErrNotDraining) {
+		t.Errorf("finish before drain error: %v", err)
+	}
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+	m.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 10)
+		return nil
+	})
+
+	if err := m.Drain(); err != nil {
+		t.Fatalf("drain error: %v", err)
+	}
+	if err := m.Drain(); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("second drain error: %v", err)
+	}
+
+	// new running jobs are rejected while draining
+	m.AddRunningJob(func(ctx context.Context) error {
+		atomic.AddInt32(&count, 100)
+		return nil
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-m.Done():
+		t.Fatal("done should not be closed before finish")
+	default:
+	}
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+
+	if err := m.Finish(); err != nil {
+		t.Fatalf("finish error: %v", err)
+	}
+	<-m.Done()
+
+	if atomic.LoadInt32(&count) != 11 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+	if err := m.Finish(); !errors.Is(err, ErrNotDraining) {
+		t.Errorf("second finish error: %v", err)
+	}
+}
+
+func TestDrainKeepsTriggers(t *testing.T) {
+	setup()
+	trigger := make(chan struct{})
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithTriggerChannel(trigger),
+	)
+
+	if err := m.Drain(); err != nil {
+		t.Fatalf("drain error: %v", err)
+	}
+
+	// the trigger channel still completes the shutdown while draining
+	close(trigger)
+	<-m.Done()
+}
+
+func TestAddDrainWaiter(t *testing.T) {
+	setup()
+	var inFlight int32 = 3
+	var closedWith int32 = -1
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithDrainPollInterval(5*time.Millisecond),
+	)
+
+	// in-flight messages are handled outside of any job
+	go func() {
+		<-m.ShutdownContext().Done()
+		for atomic.LoadInt32(&inFlight) > 0 {
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+		}
+	}()
+	m.AddDrainWaiter(func() bool {
+		return atomic.LoadInt32(&inFlight) == 0
+	})
+	m.AddShutdownJob(func() error {
+		atomic.StoreInt32(&closedWith, atomic.LoadInt32(&inFlight))
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&closedWith) != 0 {
+		t.Errorf("closed with %d messages in flight", atomic.LoadInt32(&closedWith))
+	}
+}
+
+func TestAddDrainWaiterTimeout(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(50*time.Millisecond),
+		WithDrainPollInterval(5*time.Millisecond),
+	)
+	m.AddDrainWaiter(func() bool {
+		return false
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if !m.TimedOut() {
+		t.Error("shutdown should be timed out")
+	}
+}
@@ -0,0 +1,28 @@
+package graceful
+
+import "context"
+
+// Drainable is a resource such as a worker pool that stops accepting work
+// and waits until it is idle when Drain is called. Drain should return
+// early with an error once ctx is done.
+type Drainable interface {
+	Drain(ctx context.Context) error
+}
+
+// AddDrainable add shutdown task draining d. The context passed to Drain
+// carries the values of the manager context and expires at the shutdown
+// deadline, if there is one. The error of Drain is recorded.
+func (g *Manager) AddDrainable(d Drainable) {
+	g.AddShutdownJob(func() error {
+		ctx := context.WithoutCancel(g.shutdownCtx)
+		g.lock.RLock()
+		deadline := g.shutdownDeadline
+		g.lock.RUnlock()
+		if !deadline.IsZero() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+		return d.Drain(ctx)
+	})
+}
@@ -0,0 +1,67 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type pool struct {
+	busy     time.Duration
+	drained  bool
+	deadline time.Time
+	err      error
+}
+
+func (p *pool) Drain(ctx context.Context) error {
+	p.deadline, _ = ctx.Deadline()
+	select {
+	case <-time.After(p.busy):
+		p.drained = true
+		return p.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestAddDrainable(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	p := &pool{busy: 10 * time.Millisecond}
+	m.AddDrainable(p)
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if !p.drained {
+		t.Error("pool should be drained")
+	}
+	if len(m.Errors()) != 0 {
+		t.Errorf("errors error: %v", m.Errors())
+	}
+}
+
+func TestAddDrainableDeadline(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(time.Second),
+	)
+
+	errBusy := errors.New("workers still busy")
+	p := &pool{busy: 10 * time.Millisecond, err: errBusy}
+	m.AddDrainable(p)
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	remaining := time.Until(p.deadline)
+	if remaining <= 0 || remaining > time.Second {
+		t.Errorf("deadline error: %s", remaining)
+	}
+	if errs := m.Errors(); len(errs) != 1 || !errors.Is(errs[0], errBusy) {
+		t.Errorf("errors error: %v", m.Errors())
+	}
+}
@@ -0,0 +1,32 @@
+// Package errgrouputil runs golang.org/x/sync/errgroup groups under a graceful
+// manager. It is a separate module so the core package has no dependency.
+package errgrouputil
+
+import (
+	"context"
+
+	"github.com/appleboy/graceful"
+	"golang.org/x/sync/errgroup"
+)
+
+// WithContext returns a new errgroup attached to m as the running job name,
+// and its context. The context derives from the manager shutdown context: it
+// is cancelled when the shutdown starts, or when a goroutine of the group
+// returns an error, whichever comes first. Goroutines must return once it is
+// done.
+func WithContext(m *graceful.Manager, name string) (*errgroup.Group, context.Context) {
+	eg, ctx := errgroup.WithContext(m.ShutdownContext())
+	Attach(m, name, eg)
+	return eg, ctx
+}
+
+// Attach registers a running job of m named name that waits for eg and
+// records its error, so the shutdown waits for the group. Two groups need
+// two names, or the second one replaces the first in WaitJob. Start the
+// goroutines of eg with a context derived from m.ShutdownContext(), or use
+// WithContext, so they stop when the shutdown starts.
+func Attach(m *graceful.Manager, name string, eg *errgroup.Group) {
+	m.AddNamedRunningJob(name, func(context.Context) error {
+		return eg.Wait()
+	})
+}
@@ -0,0 +1,39 @@
+package errgrouputil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/appleboy/graceful"
+)
+
+func TestWithContext(t *testing.T) {
+	m := graceful.NewIndependentManager(graceful.WithLogger(graceful.NewEmptyLogger()))
+	eg, ctx := WithContext(m, "workers")
+
+	errWorker := errors.New("worker error")
+	eg.Go(func() error {
+		<-ctx.Done()
+		return errWorker
+	})
+	eg.Go(func() error {
+		<-ctx.Done()
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if _, ok := m.JobDurations()["workers"]; !ok {
+		t.Errorf("durations error: %v", m.JobDurations())
+	}
+
+	errs := m.Errors()
+	if len(errs) != 1 || !errors.Is(errs[0], errWorker) {
+		t.Errorf("errors error: %v", errs)
+	}
+	if !errors.Is(context.Cause(ctx), graceful.ErrShutdownRequested) {
+		t.Errorf("context cause error: %v", context.Cause(ctx))
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/appleboy/graceful/errgrouputil
+
+go 1.21
+
+require (
+	github.com/appleboy/graceful v0.0.0
+	golang.org/x/sync v0.7.0
+)
+
+replace github.com/appleboy/graceful => ../
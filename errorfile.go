@@ -0,0 +1,48 @@
+package graceful
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// writeErrorLogFile appends the collected errors to the error log file,
+// with the name of the job that returned them when it is known
+func (g *Manager) writeErrorLogFile() {
+	if g.errorLogFile == "" {
+		return
+	}
+
+	g.lock.RLock()
+	errs := make([]error, len(g.errors))
+	copy(errs, g.errors)
+	entries := make([]errorEntry, len(g.errorEntries))
+	copy(entries, g.errorEntries)
+	g.lock.RUnlock()
+	if len(errs) == 0 {
+		return
+	}
+
+	f, err := os.OpenFile(g.errorLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		g.log().Errorf("open error log file: %v", err)
+		return
+	}
+	for i, e := range entries {
+		at := e.at.Format(time.RFC3339Nano)
+		if e.job != "" {
+			_, err = fmt.Fprintf(f, "%s job=%q error=%q\n", at, e.job, errs[i].Error())
+		} else {
+			_, err = fmt.Fprintf(f, "%s error=%q\n", at, errs[i].Error())
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err != nil {
+		g.log().Errorf("write error log file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		g.log().Errorf("close error log file: %v", err)
+	}
+}
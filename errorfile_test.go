@@ -0,0 +1,109 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithErrorLogFile(t *testing.T) {
+	setup()
+	path := filepath.Join(t.TempDir(), "errors.log")
+	if err := os.WriteFile(path, []byte("previous\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(WithLogger(NewEmptyLogger()), WithErrorLogFile(path))
+
+	m.AddNamedShutdownJob("flush", func() error {
+		return errors.New("flush failed")
+	})
+	m.AddNamedShutdownJob("close", func() error {
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || lines[0] != "previous" {
+		t.Fatalf("error log file should be appended to: %q", data)
+	}
+	if !strings.HasSuffix(lines[1], ` job="flush" error="flush failed"`) {
+		t.Errorf("error line error: %q", lines[1])
+	}
+}
+
+func TestWithErrorLogFileWithoutErrors(t *testing.T) {
+	setup()
+	path := filepath.Join(t.TempDir(), "errors.log")
+	m := NewManager(WithLogger(NewEmptyLogger()), WithErrorLogFile(path))
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("error log file should not exist: %v", err)
+	}
+}
+
+func TestWithErrorLogFileWriteFailure(t *testing.T) {
+	setup()
+	l := NewBufferLogger(10)
+	path := filepath.Join(t.TempDir(), "missing", "errors.log")
+	m := NewManager(WithLogger(l), WithErrorLogFile(path))
+
+	m.AddShutdownJob(func() error {
+		return errors.New("flush failed")
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	found := false
+	for _, msg := range l.Messages() {
+		if strings.HasPrefix(msg, "ERROR: open error log file:") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("open failure not logged: %v", l.Messages())
+	}
+}
+
+func TestWithErrorLogFileTimerErrors(t *testing.T) {
+	setup()
+	path := filepath.Join(t.TempDir(), "errors.log")
+	m := NewManager(WithLogger(NewEmptyLogger()), WithErrorLogFile(path))
+
+	m.AddTimerJob(5*time.Millisecond, func(ctx context.Context) error {
+		return errors.New("tick failed")
+	})
+	for i := 0; i < 100 && len(m.Errors()) < 2; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != len(m.Errors()) || len(lines) < 2 {
+		t.Fatalf("every error should be written: %q", data)
+	}
+	for _, line := range lines {
+		if !strings.HasSuffix(line, ` job="running-job-1" error="tick failed"`) {
+			t.Errorf("error line error: %q", line)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package graceful
+
+import "time"
+
+// eventBufferSize is how many events a slow subscriber can lag behind
+const eventBufferSize = 64
+
+// Event is a step of the shutdown lifecycle sent on the Events channel.
+// It is one of ShutdownStarted, JobCompleted, JobSkipped, TimedOut or
+// ShutdownCompleted.
+type Event interface {
+	event()
+}
+
+// ShutdownStarted is sent once the shutdown starts.
+type ShutdownStarted struct {
+	Cause error
+}
+
+// JobCompleted is sent every time a running or shutdown job returns.
+type JobCompleted struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// JobSkipped is sent when a low priority shutdown job is skipped,
+// see WithPriorityCutoff.
+type JobSkipped struct {
+	Name     string
+	Priority int
+}
+
+// TimedOut is sent when the shutdown timeout expires before all jobs returned.
+type TimedOut struct {
+	Pending []string
+}
+
+// ShutdownCompleted is the final event, sent right before Done() is closed.
+type ShutdownCompleted struct {
+	Stats Stats
+}
+
+func (ShutdownStarted) event()   {}
+func (JobCompleted) event()      {}
+func (JobSkipped) event()        {}
+func (TimedOut) event()          {}
+func (ShutdownCompleted) event() {}
+
+// Events returns a channel that receives the shutdown lifecycle events and
+// is closed after ShutdownCompleted. Events are dropped for a subscriber
+// whose buffer is full, so a slow reader never stalls the shutdown.
+// Subscribe before the shutdown starts to see every event.
+func (g *Manager) Events() <-chan Event {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	ch := make(chan Event, eventBufferSize)
+	if g.eventsClosed {
+		close(ch)
+		return ch
+	}
+	g.eventSubs = append(g.eventSubs, ch)
+	return ch
+}
+
+// emit sends e to every subscriber without blocking
+func (g *Manager) emit(e Event) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	for _, ch := range g.eventSubs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// closeEvents closes every subscriber channel after the final event
+func (g *Manager) closeEvents() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for _, ch := range g.eventSubs {
+		close(ch)
+	}
+	g.eventSubs = nil
+	g.eventsClosed = true
+}
@@ -0,0 +1,54 @@
+package graceful
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	setup()
+	block := make(chan struct{})
+	defer close(block)
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(50*time.Millisecond),
+	)
+	events := m.Events()
+
+	errFlush := errors.New("flush error")
+	m.AddNamedShutdownJob("flush", func() error {
+		return errFlush
+	})
+	m.AddShutdownJobToPhase(1, func() error {
+		<-block
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	var got []Event
+	for e := range events {
+		got = append(got, e)
+	}
+	if len(got) != 4 {
+		t.Fatalf("events error: %#v", got)
+	}
+	if e, ok := got[0].(ShutdownStarted); !ok || !errors.Is(e.Cause, ErrShutdownRequested) {
+		t.Errorf("first event error: %#v", got[0])
+	}
+	if e, ok := got[1].(JobCompleted); !ok || e.Name != "flush" || !errors.Is(e.Err, errFlush) {
+		t.Errorf("job event error: %#v", got[1])
+	}
+	if e, ok := got[2].(TimedOut); !ok || len(e.Pending) != 1 || e.Pending[0] != "shutdown-job-1" {
+		t.Errorf("timeout event error: %#v", got[2])
+	}
+	if e, ok := got[3].(ShutdownCompleted); !ok || e.Stats.Errors != 1 {
+		t.Errorf("final event error: %#v", got[3])
+	}
+
+	if _, ok := <-m.Events(); ok {
+		t.Error("events should be closed after the shutdown")
+	}
+}
@@ -0,0 +1,26 @@
+package graceful
+
+import "fmt"
+
+// RunShutdownJobsOnPanic runs the graceful shutdown when the function that
+// deferred it returns or panics, then waits for Done(). A panic is re-raised
+// once the shutdown completed. Defer it at the top of main:
+//
+//	m := graceful.NewManager()
+//	defer m.RunShutdownJobsOnPanic()
+//
+// Go has no exit hooks: panics in other goroutines and calls to os.Exit,
+// including log.Fatal, terminate the process without running it.
+func (g *Manager) RunShutdownJobsOnPanic() {
+	r := recover()
+	if r != nil {
+		g.log().Errorf("panic: %v. Shutting down...", r)
+		g.shutdownWithCause(fmt.Errorf("graceful: panic: %v", r))
+	} else {
+		g.shutdownWithCause(ErrShutdownRequested)
+	}
+	<-g.Done()
+	if r != nil {
+		panic(r)
+	}
+}
@@ -0,0 +1,48 @@
+package graceful
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunShutdownJobsOnPanic(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	m.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("panic should be re-raised: %v", r)
+			}
+		}()
+		defer m.RunShutdownJobsOnPanic()
+		panic("boom")
+	}()
+
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+}
+
+func TestRunShutdownJobsOnReturn(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	m.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	func() {
+		defer m.RunShutdownJobsOnPanic()
+	}()
+
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+}
@@ -0,0 +1,44 @@
+package graceful
+
+import "fmt"
+
+// AddFinalJob add task that runs after every shutdown job, and is safe to
+// call from within a shutdown job, when the other Add methods no longer
+// apply. Final jobs run one at a time in the order they were added, and
+// final jobs may add more final jobs. They run even when a shutdown job
+// panic skipped the remaining shutdown jobs, and Done is not closed before
+// they returned unless the shutdown timed out. Once the last final job
+// returned, new final jobs are rejected and logged.
+func (g *Manager) AddFinalJob(f func() error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.finalJobsClosed {
+		g.logger.Errorf("final job rejected: final jobs already ran")
+		return
+	}
+	g.finalJobCount++
+	g.finalJobs = append(g.finalJobs, shutdownJob{
+		name: fmt.Sprintf("final-job-%d", g.finalJobCount),
+		fn:   f,
+	})
+}
+
+// runFinalJobs runs the final jobs until none are left
+func (g *Manager) runFinalJobs() {
+	for {
+		g.lock.Lock()
+		jobs := g.finalJobs
+		g.finalJobs = nil
+		if len(jobs) == 0 {
+			g.finalJobsClosed = true
+		}
+		g.lock.Unlock()
+		if len(jobs) == 0 {
+			return
+		}
+
+		for _, job := range jobs {
+			g.runJob("final", job.name, job.fn)
+		}
+	}
+}
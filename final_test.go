@@ -0,0 +1,59 @@
+package graceful
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestAddFinalJob(t *testing.T) {
+	setup()
+	l := NewBufferLogger(20)
+	m := NewManager(WithLogger(l))
+
+	var lock sync.Mutex
+	var order []string
+	record := func(s string) {
+		lock.Lock()
+		order = append(order, s)
+		lock.Unlock()
+	}
+
+	m.AddShutdownJob(func() error {
+		record("shutdown")
+		m.AddFinalJob(func() error {
+			record("final 1")
+			m.AddFinalJob(func() error {
+				record("final 3")
+				return nil
+			})
+			return errors.New("final failed")
+		})
+		m.AddFinalJob(func() error {
+			record("final 2")
+			return nil
+		})
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	want := []string{"shutdown", "final 1", "final 2", "final 3"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("order error: %v", order)
+	}
+	if len(m.Errors()) != 1 {
+		t.Errorf("errors count error: %d", len(m.Errors()))
+	}
+
+	m.AddFinalJob(func() error {
+		t.Error("final job added after Done should not run")
+		return nil
+	})
+	msgs := l.Messages()
+	if msgs[len(msgs)-1] != "ERROR: final job rejected: final jobs already ran" {
+		t.Errorf("rejection not logged: %v", msgs)
+	}
+}
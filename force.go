@@ -0,0 +1,51 @@
+package graceful
+
+import (
+	"os"
+	"time"
+)
+
+// forceExitBudget bounds the shutdown jobs pass before a forced exit
+const forceExitBudget = time.Second
+
+// forceExit exits right away on a second shutdown signal, optionally after
+// running the shutdown jobs that have not started yet.
+func (g *Manager) forceExit(sig os.Signal) {
+	g.errorfContext(g.shutdownCtx, "PID %s. Received %s again. Forcing exit...", g.processID(), signalName(sig))
+	if g.shutdownJobsOnForceExit {
+		g.runShutdownJobsNow(forceExitBudget)
+	}
+	g.lock.RLock()
+	exit := g.exitFunc
+	g.lock.RUnlock()
+	exit(1)
+}
+
+// runShutdownJobsNow runs every shutdown job that has not started yet, one
+// after another in phase order, and gives up after budget. Jobs already
+// running are not waited for.
+func (g *Manager) runShutdownJobsNow(budget time.Duration) {
+	g.lock.RLock()
+	jobs := make([]shutdownJob, len(g.runAtShutdown))
+	copy(jobs, g.runAtShutdown)
+	g.lock.RUnlock()
+	sortByPhase(jobs)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, job := range jobs {
+			if job.started.CompareAndSwap(false, true) {
+				g.doShutdownJob(job.name, job.fn)
+			}
+		}
+	}()
+
+	t := time.NewTimer(budget)
+	defer t.Stop()
+	select {
+	case <-done:
+	case <-t.C:
+		g.log().Errorf("shutdown jobs still running after %s, exiting anyway", budget)
+	}
+}
@@ -0,0 +1,165 @@
+package graceful
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestForceExitOnSecondSignal(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithRunShutdownJobsBeforeForceExit(),
+	)
+	exited := make(chan int, 1)
+	m.lock.Lock()
+	m.exitFunc = func(code int) {
+		exited <- code
+	}
+	m.lock.Unlock()
+
+	block := make(chan struct{})
+	// ignores its context, so the shutdown jobs never start on their own
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	m.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+	<-m.Started()
+
+	sendSignal(t, syscall.SIGTERM)
+	<-m.ShutdownContext().Done()
+	sendSignal(t, syscall.SIGTERM)
+
+	select {
+	case code := <-exited:
+		if code != 1 {
+			t.Errorf("exit code error: %d", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second signal should force the exit")
+	}
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+
+	// the shutdown job already ran and is not run again
+	close(block)
+	<-m.Done()
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+}
+
+func TestForceExitSkipsShutdownJobs(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	exited := make(chan int, 1)
+	m.lock.Lock()
+	m.exitFunc = func(code int) {
+		exited <- code
+	}
+	m.lock.Unlock()
+
+	var count int32 = 0
+	m.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	m.forceExit(syscall.SIGINT)
+	if code := <-exited; code != 1 {
+		t.Errorf("exit code error: %d", code)
+	}
+	if atomic.LoadInt32(&count) != 0 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestNoForceExitAfterProgrammaticShutdown(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	exited := make(chan int, 1)
+	m.lock.Lock()
+	m.exitFunc = func(code int) {
+		exited <- code
+	}
+	m.lock.Unlock()
+
+	block := make(chan struct{})
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	<-m.Started()
+
+	m.DoGracefulShutdown()
+	<-m.ShutdownContext().Done()
+	sendSignal(t, syscall.SIGTERM)
+	for i := 0; i < 100 && m.SignalCount() < 1; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	select {
+	case <-exited:
+		t.Fatal("the first signal after DoGracefulShutdown should not force the exit")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(block)
+	<-m.Done()
+}
+
+func TestRunShutdownJobsNowSkipsRunningJobs(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	m.AddShutdownJob(func() error {
+		close(started)
+		<-block
+		return nil
+	})
+	var order []int
+	var lock sync.Mutex
+	record := func(phase int) ShtdownJob {
+		return func() error {
+			lock.Lock()
+			order = append(order, phase)
+			lock.Unlock()
+			return nil
+		}
+	}
+	m.AddShutdownJobToPhase(2, record(2))
+	m.AddShutdownJobToPhase(1, record(1))
+
+	m.DoGracefulShutdown()
+	<-started
+
+	// the hung phase 0 job does not hold up the jobs that never started
+	m.runShutdownJobsNow(time.Second)
+	lock.Lock()
+	if want := []int{1, 2}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order error: %v", order)
+	}
+	lock.Unlock()
+
+	close(block)
+	<-m.Done()
+	if len(order) != 2 {
+		t.Errorf("jobs should run once: %v", order)
+	}
+}
@@ -0,0 +1,29 @@
+// Package gracefultest provides helpers for testing code built on graceful.
+package gracefultest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/appleboy/graceful"
+)
+
+// Trigger starts the graceful shutdown of m, like DoGracefulShutdown. The
+// shutdown cause is graceful.ErrShutdownRequested, not a *graceful.SignalError.
+func Trigger(m *graceful.Manager) {
+	m.DoGracefulShutdown()
+}
+
+// WaitDone blocks until m is done and fails the test if it takes longer than timeout.
+func WaitDone(t testing.TB, m *graceful.Manager, timeout time.Duration) {
+	t.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-m.Done():
+	case <-timer.C:
+		t.Fatalf("graceful manager not done after %s", timeout)
+	}
+}
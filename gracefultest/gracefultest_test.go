@@ -0,0 +1,32 @@
+package gracefultest
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/appleboy/graceful"
+)
+
+func TestTriggerAndWaitDone(t *testing.T) {
+	var count int32 = 0
+	m := graceful.NewIndependentManager(graceful.WithLogger(graceful.NewEmptyLogger()))
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+	m.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	Trigger(m)
+	WaitDone(t, m, time.Second)
+
+	if atomic.LoadInt32(&count) != 2 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+}
@@ -0,0 +1,80 @@
+package graceful
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// Group is a collection of goroutines bound to the manager lifecycle.
+// The first goroutine returning a non-nil error triggers the manager shutdown.
+type Group struct {
+	manager *Manager
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// Group returns a new Group and the shutdown context its goroutines should observe.
+func (g *Manager) Group() (*Group, context.Context) {
+	return &Group{manager: g}, g.shutdownCtx
+}
+
+// Go calls the given function in a new goroutine tracked by the manager.
+// A panic in f is recovered, unless WithRecover(false), and counts as an
+// error of f.
+func (eg *Group) Go(f func() error) {
+	eg.wg.Add(1)
+	eg.manager.runningWaitGroup.Run(func() {
+		defer eg.wg.Done()
+		if err := eg.call(f); err != nil {
+			eg.manager.recordRunningError("", err)
+			eg.errOnce.Do(func() {
+				eg.err = err
+				eg.manager.shutdownWithCause(err)
+			})
+		}
+	})
+}
+
+// call runs f and returns its panic as a *PanicError
+func (eg *Group) call(f func() error) (err error) {
+	defer func() {
+		var r interface{}
+		if eg.manager.recoverPanics {
+			r = recover()
+		}
+		if r != nil {
+			err = &PanicError{Kind: "group", Value: r, Stack: debug.Stack()}
+			eg.manager.log().Error(err)
+		}
+	}()
+	return f()
+}
+
+// Wait blocks until all goroutines in the group have returned,
+// then returns the first non-nil error (if any) from them.
+func (eg *Group) Wait() error {
+	eg.wg.Wait()
+	return eg.err
+}
+
+// Go runs fn in a new goroutine that the shutdown waits for, like a running
+// job without context or error. A panic in fn is recovered and recorded.
+// Side tasks should finish on their own soon after the shutdown starts.
+func (g *Manager) Go(fn func()) {
+	g.runningWaitGroup.Run(func() {
+		defer func() {
+			var r interface{}
+			if g.recoverPanics {
+				r = recover()
+			}
+			if r != nil {
+				err := &PanicError{Kind: "background", Value: r, Stack: debug.Stack()}
+				g.log().Error(err)
+				g.recordRunningError("", err)
+			}
+		}()
+		fn()
+	})
+}
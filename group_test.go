@@ -0,0 +1,102 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	eg, ctx := m.Group()
+	eg.Go(func() error {
+		<-ctx.Done()
+		return nil
+	})
+	eg.Go(func() error {
+		return errors.New("group error")
+	})
+
+	if err := eg.Wait(); err == nil || err.Error() != "group error" {
+		t.Errorf("group error: %v", err)
+	}
+
+	<-m.Done()
+
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+	if !errors.Is(m.ShutdownContext().Err(), context.Canceled) {
+		t.Errorf("shutdown context error: %v", m.ShutdownContext().Err())
+	}
+}
+
+func TestManagerGo(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	release := make(chan struct{})
+	m.Go(func() {
+		<-release
+		atomic.AddInt32(&count, 1)
+	})
+	m.Go(func() {
+		panic("boom")
+	})
+
+	m.DoGracefulShutdown()
+	select {
+	case <-m.Done():
+		t.Fatal("done should wait for the side task")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-m.Done()
+
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+	var perr *PanicError
+	errs := m.Errors()
+	if len(errs) != 1 || !errors.As(errs[0], &perr) || perr.Kind != "background" {
+		t.Errorf("errors error: %v", errs)
+	}
+}
+
+func TestGroupPanic(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	eg, ctx := m.Group()
+
+	eg.Go(func() error {
+		panic("boom")
+	})
+	eg.Go(func() error {
+		<-ctx.Done()
+		return nil
+	})
+
+	var perr *PanicError
+	if err := eg.Wait(); !errors.As(err, &perr) || perr.Kind != "group" || perr.Value != "boom" {
+		t.Errorf("wait error: %v", err)
+	}
+	<-m.Done()
+
+	if !errors.As(m.ShutdownCause(), &perr) {
+		t.Errorf("cause error: %v", m.ShutdownCause())
+	}
+	if len(m.Errors()) != 1 {
+		t.Errorf("errors count error: %d", len(m.Errors()))
+	}
+}
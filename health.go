@@ -0,0 +1,63 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+)
+
+// healthCheck reports the liveness of a running job
+type healthCheck struct {
+	name  string
+	check func() error
+}
+
+// AddRunningJobWithHealth add running task with a health check.
+// The health check is consulted by Healthy until the job returns.
+func (g *Manager) AddRunningJobWithHealth(name string, f RunningJob, health func() error) {
+	hc := &healthCheck{name: name, check: health}
+	g.addHealthCheck(hc)
+
+	g.AddNamedRunningJob(name, func(ctx context.Context) error {
+		// a restarted job is checked again
+		g.addHealthCheck(hc)
+		defer g.removeHealthCheck(hc)
+		return f(ctx)
+	})
+}
+
+func (g *Manager) addHealthCheck(hc *healthCheck) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for _, c := range g.healthChecks {
+		if c == hc {
+			return
+		}
+	}
+	g.healthChecks = append(g.healthChecks, hc)
+}
+
+func (g *Manager) removeHealthCheck(hc *healthCheck) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for i, c := range g.healthChecks {
+		if c == hc {
+			g.healthChecks = append(g.healthChecks[:i], g.healthChecks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Healthy returns the first error reported by the health checks of running jobs.
+func (g *Manager) Healthy() error {
+	g.lock.RLock()
+	checks := make([]*healthCheck, len(g.healthChecks))
+	copy(checks, g.healthChecks)
+	g.lock.RUnlock()
+
+	for _, hc := range checks {
+		if err := hc.check(); err != nil {
+			return fmt.Errorf("job %q is unhealthy: %w", hc.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHealthy(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	errDB := errors.New("connection refused")
+
+	m.AddRunningJobWithHealth("db", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}, func() error {
+		return errDB
+	})
+
+	err := m.Healthy()
+	if !errors.Is(err, errDB) {
+		t.Errorf("health error: %v", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), `"db"`) {
+		t.Errorf("missing job name: %v", err)
+	}
+
+	m.doGracefulShutdown()
+	<-m.Done()
+
+	if err := m.Healthy(); err != nil {
+		t.Errorf("finished job should be ignored: %v", err)
+	}
+}
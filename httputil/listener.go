@@ -0,0 +1,45 @@
+package httputil
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// WrapListener returns a listener counting its open connections,
+// and a function returning that count.
+func WrapListener(l net.Listener) (net.Listener, func() int) {
+	cl := &countingListener{Listener: l}
+	return cl, cl.active
+}
+
+type countingListener struct {
+	net.Listener
+	count int64
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt64(&l.count, 1)
+	return &countingConn{Conn: c, listener: l}, nil
+}
+
+func (l *countingListener) active() int {
+	return int(atomic.LoadInt64(&l.count))
+}
+
+type countingConn struct {
+	net.Conn
+	listener *countingListener
+	once     sync.Once
+}
+
+func (c *countingConn) Close() error {
+	c.once.Do(func() {
+		atomic.AddInt64(&c.listener.count, -1)
+	})
+	return c.Conn.Close()
+}
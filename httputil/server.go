@@ -0,0 +1,181 @@
+// Package httputil runs net/http servers as graceful running jobs.
+package httputil
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/appleboy/graceful"
+)
+
+// pollInterval is how often the active connections are checked while draining
+const pollInterval = 10 * time.Millisecond
+
+// Option configures AddHTTPServer.
+type Option func(*options)
+
+type options struct {
+	listener      net.Listener
+	restarts      int
+	backoff       time.Duration
+	noBaseContext bool
+}
+
+// WithListener serves on l instead of listening on the server address.
+func WithListener(l net.Listener) Option {
+	return func(o *options) {
+		o.listener = l
+	}
+}
+
+// WithServerRestart relaunches the server up to attempts times, waiting
+// backoff in between, when it fails with an error other than
+// http.ErrServerClosed. Restarts listen on the server address again, a
+// listener given with WithListener is only used for the first run. The last
+// error is recorded by the manager once every attempt failed.
+func WithServerRestart(attempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.restarts = attempts
+		o.backoff = backoff
+	}
+}
+
+// WithoutBaseContext keeps request contexts independent of the manager, so
+// they are not canceled when the shutdown starts, see AddHTTPServer.
+func WithoutBaseContext() Option {
+	return func(o *options) {
+		o.noBaseContext = true
+	}
+}
+
+// AddHTTPServer serves srv as a running job of m. When the shutdown starts,
+// the server stops accepting connections and the job returns once every
+// connection, including hijacked ones, is closed. The manager shutdown
+// deadline bounds that wait: once it expires the server is closed and the
+// job returns, hijacked connections are left to their handlers. It returns a
+// function reporting the number of active connections.
+//
+// Unless srv has a BaseContext or WithoutBaseContext is given, request
+// contexts derive from the manager shutdown context: they are canceled as
+// soon as the shutdown starts, so handlers can abort long operations while
+// the server drains. Per-request timeouts still apply, a request context
+// ends at the timeout or at the shutdown, whichever comes first. Handlers
+// that must finish in-flight work should not stop on ctx.Done().
+func AddHTTPServer(m *graceful.Manager, srv *http.Server, opts ...Option) func() int {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if !o.noBaseContext && srv.BaseContext == nil {
+		srv.BaseContext = func(net.Listener) context.Context {
+			return m.ShutdownContext()
+		}
+	}
+
+	s := &server{srv: srv, opts: o, deadline: m.ShutdownDeadline}
+	m.AddRunningJob(s.run)
+
+	return s.activeConns
+}
+
+// server tracks the listener of the current run
+type server struct {
+	srv    *http.Server
+	opts   *options
+	lock   sync.Mutex
+	active func() int
+	// deadline returns the manager shutdown deadline
+	deadline func() (time.Time, bool)
+}
+
+// activeConns returns the connections of the current run
+func (s *server) activeConns() int {
+	s.lock.Lock()
+	active := s.active
+	s.lock.Unlock()
+	if active == nil {
+		return 0
+	}
+	return active()
+}
+
+// run serves until ctx is done, restarting on failure if configured
+func (s *server) run(ctx context.Context) error {
+	l := s.opts.listener
+	for attempt := 0; ; attempt++ {
+		err := s.serve(ctx, l)
+		if err == nil || ctx.Err() != nil || attempt >= s.opts.restarts {
+			return err
+		}
+
+		l = nil
+		t := time.NewTimer(s.opts.backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// serve runs the server once on l, or on the server address if l is nil
+func (s *server) serve(ctx context.Context, l net.Listener) error {
+	if l == nil {
+		var err error
+		if l, err = net.Listen("tcp", s.srv.Addr); err != nil {
+			return err
+		}
+	}
+	l, active := WrapListener(l)
+	s.lock.Lock()
+	s.active = active
+	s.lock.Unlock()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.srv.Serve(l)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	drainCtx, cancel := s.drainContext()
+	defer cancel()
+	err := s.srv.Shutdown(drainCtx)
+	for err == nil && active() > 0 {
+		select {
+		case <-drainCtx.Done():
+			err = drainCtx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+	if err != nil {
+		// the manager gives up at the deadline, close what is left
+		// so no goroutine outlives it
+		_ = s.srv.Close()
+	}
+	return err
+}
+
+// drainContext returns a context ending at the manager shutdown deadline,
+// or never without one
+func (s *server) drainContext() (context.Context, context.CancelFunc) {
+	if s.deadline != nil {
+		if d, ok := s.deadline(); ok {
+			return context.WithDeadline(context.Background(), d)
+		}
+	}
+	return context.WithCancel(context.Background())
+}
@@ -0,0 +1,226 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/appleboy/graceful"
+)
+
+func TestWrapListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	l, active := WrapListener(ln)
+	defer l.Close()
+
+	go func() {
+		c, err := net.Dial("tcp", l.Addr().String())
+		if err == nil {
+			defer c.Close()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}()
+
+	c, err := l.Accept()
+	if err != nil {
+		t.Fatalf("accept error: %v", err)
+	}
+	if active() != 1 {
+		t.Errorf("active error: %d", active())
+	}
+	c.Close()
+	c.Close()
+	if active() != 0 {
+		t.Errorf("active error: %d", active())
+	}
+}
+
+func TestAddHTTPServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+
+	m := graceful.NewIndependentManager(graceful.WithLogger(graceful.NewEmptyLogger()))
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+			_, _ = io.WriteString(w, "ok")
+		}),
+		ReadHeaderTimeout: time.Second,
+	}
+	active := AddHTTPServer(m, srv, WithListener(ln))
+
+	done := make(chan string)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			done <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		done <- string(body)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if active() != 1 {
+		t.Errorf("active error: %d", active())
+	}
+
+	m.DoGracefulShutdown()
+	if body := <-done; body != "ok" {
+		t.Errorf("in-flight request error: %v", body)
+	}
+	<-m.Done()
+
+	if len(m.Errors()) != 0 {
+		t.Errorf("unexpected errors: %v", m.Errors())
+	}
+	if active() != 0 {
+		t.Errorf("active error: %d", active())
+	}
+}
+
+func TestServerRestart(t *testing.T) {
+	// hold the port so the first run fails to bind
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	addr := busy.Addr().String()
+
+	s := &server{
+		srv: &http.Server{
+			Addr: addr,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "ok")
+			}),
+			ReadHeaderTimeout: time.Second,
+		},
+		opts: &options{restarts: 10, backoff: 20 * time.Millisecond},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.run(ctx)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	busy.Close()
+
+	var body []byte
+	for i := 0; i < 50 && body == nil; i++ {
+		time.Sleep(20 * time.Millisecond)
+		if resp, err := http.Get("http://" + addr); err == nil {
+			body, _ = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+	}
+	if string(body) != "ok" {
+		t.Errorf("restarted server error: %q", body)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("run error: %v", err)
+	}
+}
+
+func TestServerRestartGiveUp(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	defer busy.Close()
+
+	s := &server{
+		srv:  &http.Server{Addr: busy.Addr().String(), ReadHeaderTimeout: time.Second},
+		opts: &options{restarts: 2, backoff: time.Millisecond},
+	}
+	if err := s.run(context.Background()); err == nil {
+		t.Error("run should return the last error")
+	}
+}
+
+func TestAddHTTPServerBaseContext(t *testing.T) {
+	m := graceful.NewIndependentManager(graceful.WithLogger(graceful.NewEmptyLogger()))
+
+	srv := &http.Server{ReadHeaderTimeout: time.Second}
+	AddHTTPServer(m, srv, WithListener(newListener(t)))
+	if srv.BaseContext == nil || srv.BaseContext(nil) != m.ShutdownContext() {
+		t.Error("base context should be the shutdown context")
+	}
+
+	own := &http.Server{ReadHeaderTimeout: time.Second}
+	AddHTTPServer(m, own, WithListener(newListener(t)), WithoutBaseContext())
+	if own.BaseContext != nil {
+		t.Error("base context should not be set")
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+	if len(m.Errors()) != 0 {
+		t.Errorf("unexpected errors: %v", m.Errors())
+	}
+}
+
+func newListener(t *testing.T) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	return ln
+}
+
+func TestServeDrainDeadline(t *testing.T) {
+	ln := newListener(t)
+	hijacked := make(chan struct{})
+	s := &server{
+		srv: &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// keeps the hijacked connection open, like a websocket
+				_, _, _ = w.(http.Hijacker).Hijack()
+				close(hijacked)
+			}),
+			ReadHeaderTimeout: time.Second,
+		},
+		opts: &options{},
+		deadline: func() (time.Time, bool) {
+			return time.Now().Add(50 * time.Millisecond), true
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.serve(ctx, ln)
+	}()
+
+	c, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer c.Close()
+	_, _ = io.WriteString(c, "GET / HTTP/1.1\r\nHost: x\r\n\r\n")
+	<-hijacked
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("serve error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serve should give up at the deadline")
+	}
+}
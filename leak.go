@@ -0,0 +1,38 @@
+package graceful
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// AssertNoLeaks waits up to grace for the shutdown to complete and for the
+// number of goroutines to fall back to what it was when the manager started.
+// It returns an error describing the growth otherwise.
+//
+// The goroutine count is process wide, so anything else starting or stopping
+// goroutines in the meantime skews the result. Only use it in tests.
+func (g *Manager) AssertNoLeaks(grace time.Duration) error {
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-g.Done():
+	case <-timer.C:
+		return fmt.Errorf("graceful: shutdown did not complete within %s", grace)
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		leaked := runtime.NumGoroutine() - g.baseGoroutines
+		if leaked <= 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return fmt.Errorf("graceful: %d goroutines still running %s after shutdown", leaked, grace)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAssertNoLeaks(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	if err := m.AssertNoLeaks(time.Second); err != nil {
+		t.Errorf("unexpected leak: %v", err)
+	}
+}
+
+func TestAssertNoLeaksDetectsLeak(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	block := make(chan struct{})
+	defer close(block)
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		// the spawned goroutine ignores the shutdown context
+		go func() {
+			<-block
+		}()
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	if err := m.AssertNoLeaks(100 * time.Millisecond); err == nil {
+		t.Error("expected a leak to be reported")
+	}
+}
@@ -0,0 +1,36 @@
+package graceful
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+
+type jobKey struct{}
+
+// jobScope identifies the running job owning a context
+type jobScope struct {
+	manager *Manager
+	name    string
+}
+
+// LoggerFromContext returns the logger of the running job owning ctx. With
+// the slog logger its records carry a "job" attribute set to the job name.
+// It returns the default logger when ctx holds none.
+func LoggerFromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return l
+	}
+	return NewLogger()
+}
+
+// jobContext returns ctx carrying the logger of the named job
+func (g *Manager) jobContext(ctx context.Context, name string) context.Context {
+	l := g.log()
+	if s, ok := l.(slogLogger); ok {
+		l = slogLogger{logger: s.logger.With(slog.String("job", name))}
+	}
+	ctx = context.WithValue(ctx, jobKey{}, jobScope{manager: g, name: name})
+	return context.WithValue(ctx, loggerKey{}, l)
+}
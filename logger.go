@@ -1,9 +1,13 @@
 package graceful
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
+	"sync"
 )
 
 // Logger interface is used throughout gorush
@@ -16,6 +20,34 @@ type Logger interface {
 	Fatal(args ...interface{})
 }
 
+// ContextLogger is implemented by loggers that accept a context, such as the
+// slog logger. The manager uses it when it logs on behalf of its context, so
+// handlers reading trace IDs from the context see them.
+type ContextLogger interface {
+	InfofContext(ctx context.Context, format string, args ...interface{})
+	ErrorfContext(ctx context.Context, format string, args ...interface{})
+}
+
+// infofContext logs through ContextLogger when the logger implements it
+func (g *Manager) infofContext(ctx context.Context, format string, args ...interface{}) {
+	l := g.log()
+	if cl, ok := l.(ContextLogger); ok {
+		cl.InfofContext(ctx, format, args...)
+		return
+	}
+	l.Infof(format, args...)
+}
+
+// errorfContext logs through ContextLogger when the logger implements it
+func (g *Manager) errorfContext(ctx context.Context, format string, args ...interface{}) {
+	l := g.log()
+	if cl, ok := l.(ContextLogger); ok {
+		cl.ErrorfContext(ctx, format, args...)
+		return
+	}
+	l.Errorf(format, args...)
+}
+
 // NewLogger for simple logger.
 func NewLogger() Logger {
 	return defaultLogger{
@@ -25,6 +57,18 @@ func NewLogger() Logger {
 	}
 }
 
+// NewWriterLogger for simple logger writing info messages to out and errors
+// to errOut, with level prefixes and no timestamps. Error and fatal messages
+// share errOut, which must be safe for concurrent writes if the manager logs
+// from several goroutines.
+func NewWriterLogger(out, errOut io.Writer) Logger {
+	return defaultLogger{
+		infoLogger:  log.New(out, "INFO: ", 0),
+		errorLogger: log.New(errOut, "ERROR: ", 0),
+		fatalLogger: log.New(errOut, "FATAL: ", 0),
+	}
+}
+
 type defaultLogger struct {
 	infoLogger  *log.Logger
 	errorLogger *log.Logger
@@ -69,3 +113,120 @@ func (l emptyLogger) Fatalf(format string, args ...interface{}) {}
 func (l emptyLogger) Info(args ...interface{})                  {}
 func (l emptyLogger) Error(args ...interface{})                 {}
 func (l emptyLogger) Fatal(args ...interface{})                 {}
+
+// NewBufferLogger returns a logger that keeps the last size messages in memory.
+func NewBufferLogger(size int) *BufferLogger {
+	if size < 1 {
+		size = 1
+	}
+	return &BufferLogger{
+		messages: make([]string, size),
+	}
+}
+
+// BufferLogger records formatted messages into a fixed size ring buffer.
+// It is safe for concurrent use, so tests can assert on what the manager
+// logged. Fatal messages count as errors and do not exit the process.
+type BufferLogger struct {
+	lock      sync.Mutex
+	messages  []string
+	next      int
+	full      bool
+	lastInfo  string
+	lastError string
+	infos     int
+	errs      int
+}
+
+func (l *BufferLogger) record(prefix, msg string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.messages[l.next] = prefix + msg
+	l.next = (l.next + 1) % len(l.messages)
+	if l.next == 0 {
+		l.full = true
+	}
+	if prefix == "INFO: " {
+		l.lastInfo = msg
+		l.infos++
+	} else {
+		l.lastError = msg
+		l.errs++
+	}
+}
+
+// Messages returns the recorded messages from oldest to newest.
+func (l *BufferLogger) Messages() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.full {
+		return append([]string(nil), l.messages[:l.next]...)
+	}
+	out := make([]string, 0, len(l.messages))
+	out = append(out, l.messages[l.next:]...)
+	return append(out, l.messages[:l.next]...)
+}
+
+// Contains reports whether any message still in the buffer contains substr.
+func (l *BufferLogger) Contains(substr string) bool {
+	for _, msg := range l.Messages() {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// LastInfo returns the last info message, without prefix.
+func (l *BufferLogger) LastInfo() string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.lastInfo
+}
+
+// LastError returns the last error or fatal message, without prefix.
+func (l *BufferLogger) LastError() string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.lastError
+}
+
+// InfoCount returns the number of info messages, including the ones
+// no longer in the buffer.
+func (l *BufferLogger) InfoCount() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.infos
+}
+
+// ErrorCount returns the number of error and fatal messages, including the
+// ones no longer in the buffer.
+func (l *BufferLogger) ErrorCount() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.errs
+}
+
+func (l *BufferLogger) Infof(format string, args ...interface{}) {
+	l.record("INFO: ", fmt.Sprintf(format, args...))
+}
+
+func (l *BufferLogger) Errorf(format string, args ...interface{}) {
+	l.record("ERROR: ", fmt.Sprintf(format, args...))
+}
+
+func (l *BufferLogger) Fatalf(format string, args ...interface{}) {
+	l.record("FATAL: ", fmt.Sprintf(format, args...))
+}
+
+func (l *BufferLogger) Info(args ...interface{}) {
+	l.record("INFO: ", fmt.Sprint(args...))
+}
+
+func (l *BufferLogger) Error(args ...interface{}) {
+	l.record("ERROR: ", fmt.Sprint(args...))
+}
+
+func (l *BufferLogger) Fatal(args ...interface{}) {
+	l.record("FATAL: ", fmt.Sprint(args...))
+}
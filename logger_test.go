@@ -1,5 +1,12 @@
 package graceful
 
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
 func ExampleNewEmptyLogger() {
 	l := NewEmptyLogger()
 	l.Info("test")
@@ -10,3 +17,86 @@ func ExampleNewEmptyLogger() {
 	l.Fatalf("test")
 	// Output:
 }
+
+func TestBufferLogger(t *testing.T) {
+	l := NewBufferLogger(3)
+	l.Info("one")
+	l.Errorf("two %d", 2)
+
+	want := []string{"INFO: one", "ERROR: two 2"}
+	if got := l.Messages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("messages error: %v", got)
+	}
+
+	l.Infof("three")
+	l.Fatal("four")
+
+	want = []string{"ERROR: two 2", "INFO: three", "FATAL: four"}
+	if got := l.Messages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("messages error: %v", got)
+	}
+	if l.LastInfo() != "three" || l.LastError() != "four" {
+		t.Errorf("last message error: %q %q", l.LastInfo(), l.LastError())
+	}
+	// the evicted message is still counted
+	if l.InfoCount() != 2 || l.ErrorCount() != 2 {
+		t.Errorf("count error: %d %d", l.InfoCount(), l.ErrorCount())
+	}
+	if !l.Contains("two") || l.Contains("one") {
+		t.Error("contains error")
+	}
+}
+
+func TestBufferLoggerWithManager(t *testing.T) {
+	setup()
+	l := NewBufferLogger(10)
+	m := NewManager(WithLogger(l))
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if !l.Contains("shutdown complete") {
+		t.Errorf("summary not recorded: %v", l.Messages())
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	l := NewBufferLogger(10)
+	m.SetLogger(l)
+
+	m.AddNamedShutdownJob("flush", func() error {
+		return nil
+	})
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	want := []string{`INFO: job "flush" finished in`, "INFO: shutdown complete: 1 shutdown jobs, 0 errors"}
+	got := l.Messages()
+	if len(got) != len(want) || !strings.HasPrefix(got[0], want[0]) || !strings.HasPrefix(got[1], want[1]) {
+		t.Errorf("messages error: %v", got)
+	}
+
+	// nil falls back to the empty logger
+	m.SetLogger(nil)
+	m.log().Info("discarded")
+}
+
+func TestWriterLogger(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewWriterLogger(&out, &errOut)
+
+	l.Infof("one %d", 1)
+	l.Info("two")
+	l.Errorf("three %d", 3)
+	l.Error("four")
+	l.Fatal("five")
+
+	if out.String() != "INFO: one 1\nINFO: two\n" {
+		t.Errorf("out error: %q", out.String())
+	}
+	if errOut.String() != "ERROR: three 3\nERROR: four\nFATAL: five\n" {
+		t.Errorf("errOut error: %q", errOut.String())
+	}
+}
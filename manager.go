@@ -2,11 +2,19 @@ package graceful
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 )
 
 // manager represents the graceful server manager interface
@@ -22,158 +30,1098 @@ type (
 
 // Manager manages the graceful shutdown process
 type Manager struct {
-	lock              *sync.RWMutex
-	shutdownCtx       context.Context
-	shutdownCtxCancel context.CancelFunc
-	doneCtx           context.Context
-	doneCtxCancel     context.CancelFunc
-	logger            Logger
-	runningWaitGroup  *routineGroup
-	errors            []error
-	runAtShutdown     []ShtdownJob
+	lock                    *sync.RWMutex
+	shutdownCtx             context.Context
+	shutdownCtxCancel       context.CancelCauseFunc
+	doneCtx                 context.Context
+	doneCtxCancel           context.CancelFunc
+	logger                  Logger
+	runningWaitGroup        *routineGroup
+	shutdownWaitGroup       *routineGroup
+	errors                  []error
+	errorEntries            []errorEntry
+	runAtShutdown           []shutdownJob
+	jobResults              map[string]jobResult
+	runningJobCount         int
+	shutdownJobCount        int
+	maxConcurrentJobs       int
+	usedJobSlots            int
+	jobQueue                []queuedJob
+	shutdownOnce            sync.Once
+	healthChecks            []*healthCheck
+	state                   state
+	shutdownStarted         chan struct{}
+	baseGoroutines          int
+	shutdownTimeout         time.Duration
+	timedOut                bool
+	onJobStart              func(name string)
+	parentDeadline          time.Time
+	shutdownStart           time.Time
+	shutdownDeadline        time.Time
+	runningErrors           []error
+	continueOnShutdownPanic bool
+	shutdownAborted         bool
+	started                 chan struct{}
+	panicHandler            func(name string, recovered interface{}) error
+	activeJobs              int
+	stackDumpSignal         os.Signal
+	jobDone                 map[string]chan struct{}
+	shutdownJobsWithDrain   bool
+	jobReady                map[string]chan struct{}
+	summaryFormatter        func(Stats) string
+	signalActions           map[os.Signal]Action
+	reloadJobs              []func() error
+	signalSubs              []chan os.Signal
+	signalsClosed           bool
+	maxErrors               int
+	droppedErrors           int
+	readyFile               string
+	readyFileCreated        bool
+	deadlineChanged         chan struct{}
+	recoverPanics           bool
+	eventSubs               []chan Event
+	eventsClosed            bool
+	dedupShutdownJobs       bool
+	dedupPolicy             DedupPolicy
+	shutdownJobsOnForceExit bool
+	signalStop              chan struct{}
+	signalHandlerDone       chan struct{}
+	jobTags                 map[string][]string
+	tagIndex                map[string][]string
+	shutdownOnPanic         bool
+	drainWaiters            []func() bool
+	drainPollInterval       time.Duration
+	paused                  bool
+	resumed                 chan struct{}
+	absoluteDeadline        time.Time
+	signalLogFormat         func(sig os.Signal, pid int) string
+	jobDeps                 map[string][]string
+	readyGates              map[string]chan struct{}
+	depErrors               map[string]error
+	signalCount             atomic.Int64
+	signalsSeen             map[string]int
+	errorLogFile            string
+	signalsPaused           atomic.Bool
+	shutdownOnReadyTimeout  bool
+	shutdownResults         map[string]interface{}
+	finalJobs               []shutdownJob
+	finalJobCount           int
+	finalJobsClosed         bool
+	shutdownConcurrency     int
+	progressInterval        time.Duration
+	cleanupJobCount         int
+	priorityCutoff          time.Duration
+	minPriority             int
+	// exitFunc terminates the process, os.Exit outside of tests
+	exitFunc      func(code int)
+	jobFuncs      map[string]RunningJob
+	oneShotJobs   map[string]struct{}
+	pidFunc       func() string
+	ignoredErrors []error
 }
 
-func (g *Manager) start(ctx context.Context) {
-	g.shutdownCtx, g.shutdownCtxCancel = context.WithCancel(ctx)
-	g.doneCtx, g.doneCtxCancel = context.WithCancel(context.Background())
+// shutdownJob is a registered shutdown task with its name and phase
+type shutdownJob struct {
+	name  string
+	phase int
+	fn    ShtdownJob
+	done  chan struct{}
+	// started guarantees the job runs at most once, even on force exit
+	started *atomic.Bool
+	// priority is set for jobs added with AddShutdownJobWithPriority
+	priority *int
+}
+
+func (g *Manager) start(o Options) {
+	// the background context only provides values, its cancellation is
+	// handled by watchContext so the shutdown cause can be recorded
+	g.shutdownCtx, g.shutdownCtxCancel = context.WithCancelCause(context.WithoutCancel(o.ctx))
+	g.doneCtx, g.doneCtxCancel = context.WithCancel(context.WithoutCancel(o.ctx))
+	g.shutdownStarted = make(chan struct{})
+	g.started = make(chan struct{})
+	g.baseGoroutines = runtime.NumGoroutine()
+
+	if o.cancelOnParentDone {
+		go g.watchContext(o.ctx)
+	}
+	if !o.disableSignalHandler {
+		g.signalActions = defaultSignalActions()
+		if o.stackDumpSignal != nil {
+			g.signalActions[o.stackDumpSignal] = ActionShutdown
+		}
+		for sig, action := range o.signalActions {
+			g.signalActions[sig] = action
+		}
+		c := make(chan os.Signal, o.signalBuffer)
+		notify := make([]os.Signal, 0, len(g.signalActions))
+		for sig := range g.signalActions {
+			notify = append(notify, sig)
+		}
+		signal.Notify(
+			c,
+			notify...,
+		)
+		g.signalStop = make(chan struct{})
+		g.signalHandlerDone = make(chan struct{})
+		go g.handleSignals(c)
+	} else {
+		g.signalsClosed = true
+	}
+	if o.triggerCh != nil {
+		go g.watchTrigger(o.triggerCh)
+	}
 
-	go g.handleSignals(ctx)
+	close(g.started)
+}
+
+// DoGracefulShutdown graceful shutdown all task.
+// Calling it more than once has no further effect.
+func (g *Manager) DoGracefulShutdown() {
+	g.doGracefulShutdown()
 }
 
 // doGracefulShutdown graceful shutdown all task
 func (g *Manager) doGracefulShutdown() {
-	g.shutdownCtxCancel()
+	g.shutdownWithCause(ErrShutdownRequested)
+}
+
+// doGracefulShutdownSync starts the shutdown and returns once it completed,
+// so tests can await completion deterministically
+func (g *Manager) doGracefulShutdownSync() {
+	g.doGracefulShutdown()
+	<-g.doneCtx.Done()
+}
+
+// shutdownWithCause starts the shutdown once, recording why it happened
+func (g *Manager) shutdownWithCause(cause error) {
+	g.shutdownOnce.Do(func() {
+		g.shutdown(cause)
+	})
+}
+
+func (g *Manager) shutdown(cause error) {
+	g.shutdownCtxCancel(cause)
+	close(g.shutdownStarted)
+	g.skipQueuedJobs()
 	// doing shutdown job
-	for _, f := range g.runAtShutdown {
-		func(run ShtdownJob) {
-			g.runningWaitGroup.Run(func() {
-				g.doShutdownJob(run)
-			})
-		}(f)
-	}
+	g.lock.Lock()
+	g.state = stateShuttingDown
+	g.shutdownStart = time.Now()
+	g.shutdownDeadline = g.shutdownDeadlineFrom(g.shutdownStart)
+	jobs := make([]shutdownJob, len(g.runAtShutdown))
+	copy(jobs, g.runAtShutdown)
+	g.lock.Unlock()
+	g.removeReadyFile()
+	g.emit(ShutdownStarted{Cause: cause})
+	go g.logProgress()
+	g.shutdownWaitGroup.Run(func() {
+		// by default cleanup starts once every running job returned
+		if !g.shutdownJobsWithDrain {
+			g.runningWaitGroup.Wait()
+		}
+		g.waitDrainWaiters()
+		g.runShutdownPhases(jobs)
+		g.runFinalJobs()
+	})
 	go func() {
 		g.waitForJobs()
+		g.logSummary()
+		g.writeErrorLogFile()
+		g.removeReadyFile()
+		g.emit(ShutdownCompleted{Stats: g.Stats()})
+		g.closeEvents()
+		g.stopSignalHandler()
 		g.lock.Lock()
 		g.doneCtxCancel()
 		g.lock.Unlock()
 	}()
 }
 
-func (g *Manager) waitForJobs() {
-	g.runningWaitGroup.Wait()
+// shutdownDeadlineFrom returns when a shutdown started at start must end,
+// or the zero time if it may take forever. The sooner of the shutdown timeout
+// and the absolute shutdown deadline wins over the deadline of the background
+// context. That deadline only applies to a shutdown started before it, when
+// it is what started the shutdown the jobs drain without a deadline.
+// Caller must hold the lock.
+func (g *Manager) shutdownDeadlineFrom(start time.Time) time.Time {
+	deadline := g.absoluteDeadline
+	if g.shutdownTimeout > 0 {
+		if d := start.Add(g.shutdownTimeout); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+	if deadline.IsZero() && start.Before(g.parentDeadline) {
+		return g.parentDeadline
+	}
+	return deadline
 }
 
-func (g *Manager) handleSignals(ctx context.Context) {
-	c := make(chan os.Signal, 1)
+// shutdownRemaining returns the time left before the shutdown deadline.
+// It returns false when the shutdown has no deadline.
+func (g *Manager) shutdownRemaining() (time.Duration, bool) {
+	g.lock.RLock()
+	deadline := g.shutdownDeadline
+	g.lock.RUnlock()
+	if deadline.IsZero() {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
 
-	signal.Notify(
-		c,
-		signals...,
-	)
+// waitForJobs waits for all jobs to return, or gives up on them
+// once the shutdown deadline expires.
+func (g *Manager) waitForJobs() {
+	done := make(chan struct{})
+	go func() {
+		g.runningWaitGroup.Wait()
+		g.shutdownWaitGroup.Wait()
+		close(done)
+	}()
+
+	for {
+		g.lock.RLock()
+		start, deadline := g.shutdownStart, g.shutdownDeadline
+		g.lock.RUnlock()
+
+		var expired <-chan time.Time
+		stop := func() bool { return false }
+		if !deadline.IsZero() {
+			timer := time.NewTimer(time.Until(deadline))
+			expired, stop = timer.C, timer.Stop
+		}
+
+		select {
+		case <-done:
+			stop()
+			return
+		case <-g.deadlineChanged:
+			// SetShutdownTimeout moved the deadline, wait again
+			stop()
+		case <-expired:
+			g.lock.Lock()
+			g.timedOut = true
+			g.lock.Unlock()
+			pending := g.PendingJobs()
+			g.log().Errorf(
+				"shutdown timed out after %s, abandoning unfinished jobs: %s",
+				time.Since(start), strings.Join(pending, ", "),
+			)
+			g.emit(TimedOut{Pending: pending})
+			return
+		}
+	}
+}
+
+func (g *Manager) handleSignals(c chan os.Signal) {
+	defer close(g.signalHandlerDone)
 	defer signal.Stop(c)
+	defer g.closeSignalSubs()
 
 	pid := syscall.Getpid()
+	id := g.processID()
+	// keep listening during the shutdown, a second shutdown signal forces the exit.
+	// Shutdowns started otherwise, e.g. by DoGracefulShutdown, still get one
+	// graceful signal so an orchestrator's SIGTERM does not cut the drain short.
+	gotShutdownSignal := false
 	for {
 		select {
 		case sig := <-c:
-			switch sig {
-			case syscall.SIGINT:
-				g.logger.Infof("PID %d. Received SIGINT. Shutting down...", pid)
-				g.doGracefulShutdown()
-				return
-			case syscall.SIGTERM:
-				g.logger.Infof("PID %d. Received SIGTERM. Shutting down...", pid)
-				g.doGracefulShutdown()
-				return
+			g.recordSignal(sig)
+			g.publishSignal(sig)
+			if g.signalsPaused.Load() {
+				g.infofContext(g.shutdownCtx, "PID %s. Received %v while signals are paused, ignoring.", id, sig)
+				continue
+			}
+			switch g.signalActions[sig] {
+			case ActionShutdown:
+				if gotShutdownSignal {
+					g.forceExit(sig)
+					return
+				}
+				if sig == g.stackDumpSignal {
+					g.infofContext(g.shutdownCtx, "PID %s. Received %v. Dumping goroutine stacks and shutting down...", id, sig)
+					g.dumpStacks()
+				} else {
+					if g.signalLogFormat != nil {
+						g.infofContext(g.shutdownCtx, "%s", g.signalLogFormat(sig, pid))
+					} else {
+						g.infofContext(g.shutdownCtx, "%s", defaultSignalLog(sig, id))
+					}
+				}
+				g.shutdownWithCause(&SignalError{Signal: sig})
+				gotShutdownSignal = true
+			case ActionReload:
+				g.infofContext(g.shutdownCtx, "PID %s. Received %v. Reloading...", id, sig)
+				g.reload()
+			case ActionStackDump:
+				g.infofContext(g.shutdownCtx, "PID %s. Received %v. Dumping goroutine stacks...", id, sig)
+				g.dumpStacks()
+			case ActionPause:
+				g.infofContext(g.shutdownCtx, "PID %s. Received %v. Pausing jobs...", id, sig)
+				g.Pause()
+			case ActionResume:
+				g.infofContext(g.shutdownCtx, "PID %s. Received %v. Resuming jobs...", id, sig)
+				g.Resume()
 			default:
-				g.logger.Infof("PID %d. Received %v.", pid, sig)
+				g.infofContext(g.shutdownCtx, "PID %s. Received %v.", id, sig)
 			}
-		case <-ctx.Done():
-			g.logger.Infof("PID: %d. Background context for manager closed - %v - Shutting down...", pid, ctx.Err())
-			g.doGracefulShutdown()
+		case <-g.signalStop:
 			return
 		}
 	}
 }
 
-// doShutdownJob execute shutdown task
-func (g *Manager) doShutdownJob(f ShtdownJob) {
-	// to handle panic cases from inside the worker
-	defer func() {
-		if err := recover(); err != nil {
-			msg := fmt.Errorf("panic in shutdown job: %v", err)
-			g.logger.Error(msg)
-			g.lock.Lock()
-			g.errors = append(g.errors, msg)
-			g.lock.Unlock()
+// stopSignalHandler stops handling signals and waits for the handler to return
+func (g *Manager) stopSignalHandler() {
+	if g.signalStop == nil {
+		return
+	}
+	close(g.signalStop)
+	<-g.signalHandlerDone
+}
+
+// dumpStacks writes the stack of every goroutine to the error logger
+func (g *Manager) dumpStacks() {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	g.log().Errorf("goroutine stacks:\n%s", buf)
+}
+
+// watchContext shuts down the manager once the background context is closed
+func (g *Manager) watchContext(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		g.infofContext(ctx, "PID: %s. Background context for manager closed - %v - Shutting down...",
+			g.processID(), ctx.Err())
+		g.shutdownWithCause(fmt.Errorf("%w: %w", ErrParentContextDone, context.Cause(ctx)))
+	case <-g.shutdownStarted:
+	}
+}
+
+// watchTrigger shuts down the manager once the trigger channel fires
+func (g *Manager) watchTrigger(ch <-chan struct{}) {
+	select {
+	case <-ch:
+		g.log().Infof("PID %s. Trigger channel fired. Shutting down...", g.processID())
+		g.shutdownWithCause(ErrTriggerChannel)
+	case <-g.shutdownStarted:
+	}
+}
+
+// ShutdownAfter starts the graceful shutdown once d elapsed, for batch jobs
+// and self terminating containers. The timer is dropped if the shutdown
+// starts earlier for any other reason.
+func (g *Manager) ShutdownAfter(d time.Duration) {
+	go func() {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+			g.log().Infof("PID %s. Shutdown delay of %s elapsed. Shutting down...", g.processID(), d)
+			g.shutdownWithCause(ErrShutdownAfter)
+		case <-g.shutdownStarted:
 		}
 	}()
-	if err := f(); err != nil {
+}
+
+// TriggerFrom runs watcher as a running job and starts the graceful shutdown
+// once it returns nil, for example when a cloud metadata endpoint announces
+// the termination ahead of SIGTERM. The context passed to watcher derives
+// from ctx and is also cancelled when the shutdown starts, so the watcher
+// stops then. A watcher error is recorded and does not shut down.
+func (g *Manager) TriggerFrom(ctx context.Context, watcher func(context.Context) error) {
+	g.AddRunningJob(func(jobCtx context.Context) error {
+		wctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(jobCtx, cancel)
+		defer stop()
+
+		err := watcher(wctx)
+		if wctx.Err() != nil {
+			// stopped by the shutdown or ctx
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		g.log().Infof("PID %s. External trigger fired. Shutting down...", g.processID())
+		g.shutdownWithCause(ErrExternalTrigger)
+		return nil
+	})
+}
+
+// doShutdownJob execute shutdown task
+func (g *Manager) doShutdownJob(name string, f ShtdownJob) {
+	if g.runJob("shutdown", name, f) && !g.continueOnShutdownPanic {
 		g.lock.Lock()
-		g.errors = append(g.errors, err)
+		g.shutdownAborted = true
 		g.lock.Unlock()
 	}
 }
 
+// runJob calls f and records its error, panic and duration
+func (g *Manager) runJob(kind, name string, f func() error) (panicked bool) {
+	start := time.Now()
+	var err error
+	defer func() {
+		// to handle panic cases from inside the worker,
+		// unless WithRecover(false) lets them crash the process
+		var r interface{}
+		if g.recoverPanics {
+			r = recover()
+		}
+		var perr *PanicError
+		if r != nil {
+			panicked = true
+			perr = &PanicError{Kind: kind, Job: name, Value: r, Stack: debug.Stack()}
+			err = perr
+			g.log().Error(err)
+			if g.panicHandler != nil {
+				err = g.panicHandler(name, r)
+			}
+		}
+		if perr == nil && kind == "running" && g.isIgnoredError(err) {
+			err = nil
+		}
+		if err != nil && kind == "running" {
+			g.recordRunningError(name, err)
+		} else if err != nil {
+			g.recordError(name, err)
+		}
+		g.recordResult(name, jobResult{
+			err:      err,
+			panicked: panicked,
+			duration: time.Since(start),
+		})
+		if perr != nil && kind == "running" && g.shutdownOnPanic {
+			g.log().Errorf("job %q panicked, shutting down...", name)
+			g.shutdownWithCause(perr)
+		}
+	}()
+	err = f()
+	return false
+}
+
+// recordError appends err of the named job to the collected errors,
+// name is empty when no job is known
+func (g *Manager) recordError(name string, err error) {
+	g.lock.Lock()
+	g.appendError(name, err)
+	g.lock.Unlock()
+}
+
+// recordRunningError appends err from the named running job to the collected errors
+func (g *Manager) recordRunningError(name string, err error) {
+	g.lock.Lock()
+	g.appendError(name, err)
+	g.runningErrors = append(g.runningErrors, err)
+	if g.maxErrors > 0 && len(g.runningErrors) > g.maxErrors {
+		g.runningErrors = g.runningErrors[len(g.runningErrors)-g.maxErrors:]
+	}
+	g.lock.Unlock()
+}
+
+// isIgnoredError reports whether err matches one of WithIgnoredErrors
+func (g *Manager) isIgnoredError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, ignored := range g.ignoredErrors {
+		if errors.Is(err, ignored) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorEntry records when and by which job a collected error was returned
+type errorEntry struct {
+	job string
+	at  time.Time
+}
+
+// appendError adds err to the collected errors, dropping the oldest
+// once maxErrors is reached. The caller must hold the lock.
+func (g *Manager) appendError(name string, err error) {
+	g.errors = append(g.errors, err)
+	g.errorEntries = append(g.errorEntries, errorEntry{job: name, at: time.Now()})
+	if g.maxErrors > 0 && len(g.errors) > g.maxErrors {
+		n := len(g.errors) - g.maxErrors
+		g.errors = append(g.errors[:0:0], g.errors[n:]...)
+		g.errorEntries = append(g.errorEntries[:0:0], g.errorEntries[n:]...)
+		g.droppedErrors += n
+	}
+}
+
+// recordResult stores the outcome of the named job
+func (g *Manager) recordResult(name string, result jobResult) {
+	result.finished = time.Now()
+	g.lock.Lock()
+	g.jobResults[name] = result
+	g.lock.Unlock()
+	g.log().Infof("job %q finished in %s", name, result.duration)
+	g.emit(JobCompleted{Name: name, Err: result.err, Duration: result.duration})
+}
+
 // AddShutdownJob add shutdown task
 func (g *Manager) AddShutdownJob(f ShtdownJob) {
+	g.addShutdownJob(shutdownJob{name: g.nextShutdownJobName(), fn: f})
+}
+
+// AddNamedShutdownJob add shutdown task with a name used in logs and timings
+func (g *Manager) AddNamedShutdownJob(name string, f ShtdownJob) {
+	g.addShutdownJob(shutdownJob{name: name, fn: f})
+}
+
+// AddShutdownJobWithErrors add shutdown task that receives a copy of the
+// errors returned by running jobs so far. Errors of shutdown jobs are not
+// part of the snapshot, even those of jobs which already finished.
+func (g *Manager) AddShutdownJobWithErrors(f func(runningErrs []error) error) {
+	g.AddShutdownJob(func() error {
+		g.lock.RLock()
+		errs := make([]error, len(g.runningErrors))
+		copy(errs, g.runningErrors)
+		g.lock.RUnlock()
+		return f(errs)
+	})
+}
+
+// NoShutdownDeadline is the remaining time passed to AddShutdownJobWithBudget
+// jobs when the shutdown has no deadline.
+const NoShutdownDeadline = time.Duration(math.MaxInt64)
+
+// AddShutdownJobWithBudget add shutdown task that receives the time left
+// before the shutdown deadline when it starts, or NoShutdownDeadline.
+// The remaining time is never negative.
+func (g *Manager) AddShutdownJobWithBudget(f func(remaining time.Duration) error) {
+	g.AddShutdownJob(func() error {
+		remaining, ok := g.shutdownRemaining()
+		if !ok {
+			remaining = NoShutdownDeadline
+		} else if remaining < 0 {
+			remaining = 0
+		}
+		return f(remaining)
+	})
+}
+
+func (g *Manager) addShutdownJob(job shutdownJob) {
 	g.lock.Lock()
-	g.runAtShutdown = append(g.runAtShutdown, f)
+	g.appendShutdownJob(job)
 	g.lock.Unlock()
 }
 
+// appendShutdownJob registers job. Caller must hold the lock.
+func (g *Manager) appendShutdownJob(job shutdownJob) {
+	job.done = make(chan struct{})
+	job.started = &atomic.Bool{}
+	if g.dedupShutdownJobs && g.dedupShutdownJob(job) {
+		return
+	}
+	g.runAtShutdown = append(g.runAtShutdown, job)
+	g.jobDone[job.name] = job.done
+}
+
+// nextShutdownJobName generates a name for an unnamed shutdown job
+func (g *Manager) nextShutdownJobName() string {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.shutdownJobCount++
+	return fmt.Sprintf("shutdown-job-%d", g.shutdownJobCount)
+}
+
 // AddRunningJob add running task
 func (g *Manager) AddRunningJob(f RunningJob) {
-	g.runningWaitGroup.Run(func() {
-		// to handle panic cases from inside the worker
-		defer func() {
-			if err := recover(); err != nil {
-				msg := fmt.Errorf("panic in running job: %v", err)
-				g.logger.Error(msg)
-				g.lock.Lock()
-				g.errors = append(g.errors, msg)
-				g.lock.Unlock()
+	g.AddNamedRunningJob(g.nextRunningJobName(), f)
+}
+
+// nextRunningJobName generates a name for an unnamed running job
+func (g *Manager) nextRunningJobName() string {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.runningJobCount++
+	return fmt.Sprintf("running-job-%d", g.runningJobCount)
+}
+
+// AddNamedRunningJob add running task with a name used in logs and timings
+func (g *Manager) AddNamedRunningJob(name string, f RunningJob) {
+	g.addRunningJob(name, f, nil)
+}
+
+// addRunningJob starts the running task. skipped, if not nil, is called
+// when f never runs because the job was rejected or cancelled before start.
+func (g *Manager) addRunningJob(name string, f RunningJob, skipped func()) {
+	g.startRunningJob(name, f, make(chan struct{}), skipped)
+}
+
+// startRunningJob starts the running task and closes done once it returned
+// or was skipped, see addRunningJob.
+func (g *Manager) startRunningJob(name string, f RunningJob, done chan struct{}, skipped func()) {
+	g.lock.RLock()
+	accepting := g.state == stateRunning
+	g.lock.RUnlock()
+	if !accepting {
+		g.log().Infof("job %q rejected: manager is no longer accepting running jobs", name)
+		close(done)
+		if skipped != nil {
+			skipped()
+		}
+		return
+	}
+
+	g.lock.Lock()
+	g.jobDone[name] = done
+	g.jobFuncs[name] = f
+	g.lock.Unlock()
+
+	g.acquireJobSlot(queuedJob{
+		run: func() {
+			g.runningWaitGroup.Run(func() {
+				defer close(done)
+				defer g.releaseJobSlot()
+				g.runRunningJob(name, f)
+			})
+		},
+		skip: func() {
+			g.log().Infof("job %q cancelled before start", name)
+			if skipped != nil {
+				skipped()
 			}
-		}()
-		if err := f(g.shutdownCtx); err != nil {
-			g.lock.Lock()
-			g.errors = append(g.errors, err)
-			g.lock.Unlock()
+			close(done)
+		},
+	})
+}
+
+// runRunningJob runs the started running task and releases the jobs
+// waiting for it
+func (g *Manager) runRunningJob(name string, f RunningJob) {
+	g.lock.Lock()
+	g.activeJobs++
+	g.lock.Unlock()
+	defer func() {
+		g.lock.Lock()
+		g.activeJobs--
+		g.lock.Unlock()
+	}()
+
+	panicked := g.runJob("running", name, func() error {
+		if g.onJobStart != nil {
+			g.onJobStart(name)
 		}
+		return f(g.jobContext(g.shutdownCtx, name))
+	})
+
+	// only a clean return counts as ready for the jobs waiting on this one
+	g.lock.RLock()
+	err := g.jobResults[name].err
+	g.lock.RUnlock()
+	switch {
+	case panicked:
+		g.markFailed(name, fmt.Errorf("job %q panicked", name))
+	case err != nil:
+		g.markFailed(name, err)
+	default:
+		g.markReady(name)
+	}
+}
+
+// AddRunningJobFunc add running task that does not take a context.
+// Such a job is not notified of shutdown through a context, so only use it
+// for work that terminates on its own. Prefer AddRunningJob otherwise.
+func (g *Manager) AddRunningJobFunc(f func() error) {
+	g.AddRunningJob(func(context.Context) error {
+		return f()
+	})
+}
+
+// AddCancelableJob add running task and returns a function that stops
+// only this job. The job is still stopped by the manager shutdown.
+// Calling it before the job started stops the job as soon as it starts.
+func (g *Manager) AddCancelableJob(f RunningJob) context.CancelFunc {
+	c := &cancelableRun{}
+	g.AddRunningJob(func(jobCtx context.Context) error {
+		ctx, cancel := c.start(jobCtx)
+		defer c.stop(cancel)
+		return f(ctx)
 	})
+	return c.cancel
+}
+
+// cancelableRun holds the cancel function of the run of a cancelable job
+type cancelableRun struct {
+	lock      sync.Mutex
+	cancelFn  context.CancelFunc
+	cancelled bool
+}
+
+// start derives the context of a run from the job context, cancelled
+// right away when cancel was called before the run started
+func (c *cancelableRun) start(jobCtx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(jobCtx)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cancelled {
+		c.cancelled = false
+		cancel()
+	} else {
+		c.cancelFn = cancel
+	}
+	return ctx, cancel
+}
+
+func (c *cancelableRun) stop(cancel context.CancelFunc) {
+	c.lock.Lock()
+	c.cancelFn = nil
+	c.lock.Unlock()
+	cancel()
+}
+
+// cancel stops the run in progress, or the next one
+func (c *cancelableRun) cancel() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cancelFn != nil {
+		c.cancelFn()
+		return
+	}
+	c.cancelled = true
+}
+
+// queuedJob is a running job waiting for a WithMaxConcurrentJobs slot
+type queuedJob struct {
+	run  func()
+	skip func()
+}
+
+// acquireJobSlot runs job right away when a slot is free, queues it
+// otherwise. Queued jobs take no goroutine until they start, and are
+// skipped if shutdown starts first.
+func (g *Manager) acquireJobSlot(job queuedJob) {
+	if g.maxConcurrentJobs == 0 {
+		job.run()
+		return
+	}
+	g.lock.Lock()
+	switch {
+	case g.shutdownCtx.Err() != nil:
+		g.lock.Unlock()
+		job.skip()
+	case g.usedJobSlots < g.maxConcurrentJobs:
+		g.usedJobSlots++
+		g.lock.Unlock()
+		job.run()
+	default:
+		g.jobQueue = append(g.jobQueue, job)
+		g.lock.Unlock()
+	}
+}
+
+// releaseJobSlot hands the slot taken by acquireJobSlot to the next
+// queued job, or frees it
+func (g *Manager) releaseJobSlot() {
+	if g.maxConcurrentJobs == 0 {
+		return
+	}
+	g.lock.Lock()
+	if len(g.jobQueue) == 0 || g.shutdownCtx.Err() != nil {
+		g.usedJobSlots--
+		g.lock.Unlock()
+		return
+	}
+	next := g.jobQueue[0]
+	g.jobQueue = g.jobQueue[1:]
+	g.lock.Unlock()
+	next.run()
 }
 
-// Done allows the manager to be viewed as a context.Context.
+// skipQueuedJobs skips the jobs still waiting for a slot once shutdown started
+func (g *Manager) skipQueuedJobs() {
+	g.lock.Lock()
+	queue := g.jobQueue
+	g.jobQueue = nil
+	g.lock.Unlock()
+	for _, job := range queue {
+		job.skip()
+	}
+}
+
+// SetLogger replaces the logger used by the manager.
+// A nil logger discards all messages.
+func (g *Manager) SetLogger(l Logger) {
+	if l == nil {
+		l = NewEmptyLogger()
+	}
+	g.lock.Lock()
+	g.logger = l
+	g.lock.Unlock()
+}
+
+// log returns the current logger
+func (g *Manager) log() Logger {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.logger
+}
+
+// Errors returns the errors collected from running and shutdown jobs.
+func (g *Manager) Errors() []error {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	errs := make([]error, len(g.errors))
+	copy(errs, g.errors)
+	return errs
+}
+
+// DroppedErrorCount returns how many errors were dropped because of
+// WithMaxErrors. It is always zero when the errors are unbounded.
+func (g *Manager) DroppedErrorCount() int {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.droppedErrors
+}
+
+// TimedOut reports whether the shutdown timeout expired
+// before all jobs returned.
+func (g *Manager) TimedOut() bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.timedOut
+}
+
+// WaitJob returns a channel closed once the named job returned.
+// The channel is already closed if no job has this name.
+func (g *Manager) WaitJob(name string) <-chan struct{} {
+	g.lock.RLock()
+	done, ok := g.jobDone[name]
+	g.lock.RUnlock()
+	if !ok {
+		done = make(chan struct{})
+		close(done)
+	}
+	return done
+}
+
+// SetShutdownTimeout changes the shutdown timeout, zero removes it. During a
+// shutdown that has not timed out yet, the deadline moves to d after the
+// shutdown start: extending gives unfinished jobs more time, shortening below
+// the time already spent times out right away. It has no effect once the
+// shutdown timed out.
+func (g *Manager) SetShutdownTimeout(d time.Duration) {
+	g.lock.Lock()
+	g.shutdownTimeout = d
+	inProgress := !g.shutdownStart.IsZero() && !g.timedOut
+	if inProgress {
+		g.shutdownDeadline = g.shutdownDeadlineFrom(g.shutdownStart)
+	}
+	g.lock.Unlock()
+
+	if inProgress {
+		select {
+		case g.deadlineChanged <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ShutdownDeadline returns when the current shutdown gives up on unfinished
+// jobs. It returns false before the shutdown started or when it has no deadline.
+func (g *Manager) ShutdownDeadline() (time.Time, bool) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.shutdownDeadline, !g.shutdownDeadline.IsZero()
+}
+
+// PendingJobs returns the sorted names of the registered jobs that have not
+// returned yet. After a shutdown timeout these are the abandoned jobs.
+func (g *Manager) PendingJobs() []string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	names := make([]string, 0)
+	for name, done := range g.jobDone {
+		select {
+		case <-done:
+		default:
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// JobDurations returns how long each finished job took, keyed by job name.
+// It is safe to call after Done() is closed.
+func (g *Manager) JobDurations() map[string]time.Duration {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	durations := make(map[string]time.Duration, len(g.jobResults))
+	for name, result := range g.jobResults {
+		durations[name] = result.duration
+	}
+	return durations
+}
+
+// Done is closed once the shutdown completed: every running and shutdown job
+// returned, or the shutdown timed out. It is safe to exit main right after.
+// See Context.
 func (g *Manager) Done() <-chan struct{} {
 	return g.doneCtx.Done()
 }
 
+// WaitContext blocks until the shutdown completed or ctx is done. It returns
+// nil in the first case and ctx.Err() in the second. Other waiters on Done()
+// are not affected.
+func (g *Manager) WaitContext(ctx context.Context) error {
+	select {
+	case <-g.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Context returns a context.Context that is canceled once the shutdown
+// completed, when Done() is closed. It carries the values of the context
+// given to WithContext. Use ShutdownContext instead for a context that is
+// canceled as soon as the shutdown starts.
+func (g *Manager) Context() context.Context {
+	return g.doneCtx
+}
+
+// DoneContext returns a context like Context whose Err reports what went
+// wrong: once Done() is closed, Err returns the errors collected from the
+// jobs joined with errors.Join, or context.Canceled if there were none.
+// This deviates from the context.Context contract, where Err is always
+// Canceled or DeadlineExceeded, so do not pass it to code relying on that.
+func (g *Manager) DoneContext() context.Context {
+	return doneContext{Context: g.doneCtx, g: g}
+}
+
+// doneContext reports the job errors as its Err
+type doneContext struct {
+	context.Context
+	g *Manager
+}
+
+func (c doneContext) Err() error {
+	err := c.Context.Err()
+	if err == nil {
+		return nil
+	}
+	if errs := c.g.Errors(); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return err
+}
+
+// Started is closed once the manager finished its setup, including the
+// signal handler. It does not tell whether the running jobs are ready.
+func (g *Manager) Started() <-chan struct{} {
+	return g.started
+}
+
 // ShutdownContext returns a context.Context that is Done at shutdown
 func (g *Manager) ShutdownContext() context.Context {
 	return g.shutdownCtx
 }
 
+// ShutdownCause returns why the shutdown started, or nil if it has not started.
+// Jobs can get the same value with context.Cause on their context.
+func (g *Manager) ShutdownCause() error {
+	return context.Cause(g.shutdownCtx)
+}
+
 func newManager(opts ...Option) *Manager {
 	startOnce.Do(func() {
-		o := newOptions(opts...)
-		manager = &Manager{
-			lock:             &sync.RWMutex{},
-			logger:           o.logger,
-			errors:           make([]error, 0),
-			runningWaitGroup: newRoutineGroup(),
-		}
-		manager.start(o.ctx)
+		manager = buildManager(opts...)
 	})
 
 	return manager
 }
 
-// NewManager initial the Manager
+// buildManager creates and starts a manager
+func buildManager(opts ...Option) *Manager {
+	o := newOptions(opts...)
+	m := &Manager{
+		lock:                    &sync.RWMutex{},
+		logger:                  o.logger,
+		errors:                  make([]error, 0),
+		jobResults:              make(map[string]jobResult),
+		jobDone:                 make(map[string]chan struct{}),
+		jobReady:                make(map[string]chan struct{}),
+		jobTags:                 make(map[string][]string),
+		tagIndex:                make(map[string][]string),
+		jobDeps:                 make(map[string][]string),
+		readyGates:              make(map[string]chan struct{}),
+		depErrors:               make(map[string]error),
+		signalsSeen:             make(map[string]int),
+		shutdownResults:         make(map[string]interface{}),
+		shutdownTimeout:         o.shutdownTimeout,
+		onJobStart:              o.onJobStart,
+		continueOnShutdownPanic: o.continueOnShutdownPanic,
+		panicHandler:            o.panicHandler,
+		stackDumpSignal:         o.stackDumpSignal,
+		runningWaitGroup:        newRoutineGroup(),
+		shutdownWaitGroup:       newRoutineGroup(),
+		shutdownJobsWithDrain:   o.shutdownJobsWithDrain,
+		summaryFormatter:        o.summaryFormatter,
+		maxErrors:               o.maxErrors,
+		readyFile:               o.readyFile,
+		deadlineChanged:         make(chan struct{}, 1),
+		recoverPanics:           o.recoverPanics,
+		dedupShutdownJobs:       o.dedupShutdownJobs,
+		dedupPolicy:             o.dedupPolicy,
+		shutdownJobsOnForceExit: o.shutdownJobsOnForceExit,
+		shutdownOnPanic:         o.shutdownOnPanic,
+		drainPollInterval:       o.drainPollInterval,
+		resumed:                 closedChan(),
+		absoluteDeadline:        o.shutdownDeadline,
+		signalLogFormat:         o.signalLogFormat,
+		errorLogFile:            o.errorLogFile,
+		shutdownOnReadyTimeout:  o.shutdownOnReadyTimeout,
+		shutdownConcurrency:     o.shutdownConcurrency,
+		progressInterval:        o.progressInterval,
+		priorityCutoff:          o.priorityCutoff,
+		minPriority:             o.minPriority,
+		exitFunc:                os.Exit,
+		jobFuncs:                make(map[string]RunningJob),
+		oneShotJobs:             make(map[string]struct{}),
+		pidFunc:                 o.pidFunc,
+		ignoredErrors:           o.ignoredErrors,
+	}
+	if deadline, ok := o.ctx.Deadline(); ok && o.cancelOnParentDone {
+		m.parentDeadline = deadline
+	}
+	if o.maxConcurrentJobs > 0 {
+		m.maxConcurrentJobs = o.maxConcurrentJobs
+	}
+	m.start(o)
+	return m
+}
+
+// NewManager initial the Manager. It creates the process-wide manager once,
+// later calls return it and ignore their options, see NewIndependentManager.
 func NewManager(opts ...Option) *Manager {
 	return newManager(opts...)
 }
 
+// NewIndependentManager creates a manager separate from the process-wide one
+// returned by NewManager and GetManager, for processes running several
+// subsystems with their own lifecycle, see CombineManagers. Each manager
+// handles signals unless WithoutSignalHandler is given.
+func NewIndependentManager(opts ...Option) *Manager {
+	return buildManager(opts...)
+}
+
 // NewManagerWithContext initial the Manager with custom context
+//
+// Deprecated: use NewManager(WithContext(ctx), opts...) instead.
 func NewManagerWithContext(ctx context.Context, opts ...Option) *Manager {
 	return newManager(append(opts, WithContext(ctx))...)
 }
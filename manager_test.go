@@ -3,7 +3,12 @@ package graceful
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"reflect"
+	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -13,6 +18,7 @@ import (
 
 func setup() {
 	startOnce = sync.Once{}
+	manager = nil
 }
 
 func TestMissingManager(t *testing.T) {
@@ -32,6 +38,12 @@ func TestManagerExist(t *testing.T) {
 	if m == nil {
 		t.Errorf("missing manager")
 	}
+
+	select {
+	case <-m.Started():
+	default:
+		t.Errorf("manager should be started")
+	}
 }
 
 func TestRunningJob(t *testing.T) {
@@ -135,10 +147,51 @@ func TestNewManagerWithContext(t *testing.T) {
 	}
 }
 
+func TestNewManagerWithContextOption(t *testing.T) {
+	setup()
+	ctx, cancel := context.WithCancel(context.Background())
+	var count int32 = 0
+	m := NewManager(WithContext(ctx))
+
+	// Add job
+	m.AddRunningJob(func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+				atomic.AddInt32(&count, 1)
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
+	})
+
+	m.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	<-m.Done()
+
+	if atomic.LoadInt32(&count) != 2 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+	if !errors.Is(m.ShutdownCause(), ErrParentContextDone) {
+		t.Errorf("shutdown cause error: %v", m.ShutdownCause())
+	}
+}
+
 func TestWithError(t *testing.T) {
 	setup()
 	ctx, cancel := context.WithCancel(context.Background())
 	var count int32 = 0
+	var started sync.WaitGroup
+	started.Add(2)
 	m := NewManagerWithContext(ctx)
 
 	// Add job
@@ -149,6 +202,7 @@ func TestWithError(t *testing.T) {
 				return nil
 			default:
 				atomic.AddInt32(&count, 1)
+				started.Done()
 				time.Sleep(100 * time.Millisecond)
 				return errors.New("first error")
 			}
@@ -162,6 +216,7 @@ func TestWithError(t *testing.T) {
 				return nil
 			default:
 				atomic.AddInt32(&count, 1)
+				started.Done()
 				time.Sleep(100 * time.Millisecond)
 				panic("four error")
 			}
@@ -178,10 +233,9 @@ func TestWithError(t *testing.T) {
 		return errors.New("three error")
 	})
 
-	go func() {
-		time.Sleep(50 * time.Millisecond)
-		cancel()
-	}()
+	// both running jobs are past their context check
+	started.Wait()
+	cancel()
 
 	<-m.Done()
 
@@ -192,6 +246,36 @@ func TestWithError(t *testing.T) {
 	if len(m.errors) != 4 {
 		t.Errorf("fail error count: %d", len(m.errors))
 	}
+	panics := 0
+	for _, err := range m.Errors() {
+		var perr *PanicError
+		if errors.As(err, &perr) {
+			panics++
+			if perr.Job == "" || len(perr.Stack) == 0 {
+				t.Errorf("panic error details missing: %+v", perr)
+			}
+		}
+	}
+	if panics != 2 {
+		t.Errorf("panic error count: %d", panics)
+	}
+}
+
+func TestPanicWithError(t *testing.T) {
+	setup()
+	errBoom := errors.New("boom")
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddShutdownJob(func() error {
+		panic(errBoom)
+	})
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	errs := m.Errors()
+	if len(errs) != 1 || !errors.Is(errs[0], errBoom) {
+		t.Errorf("errors error: %v", errs)
+	}
 }
 
 func TestGetShutdonwContext(t *testing.T) {
@@ -249,7 +333,7 @@ func testingSignal(t *testing.T, signal os.Signal) {
 	})
 
 	go func() {
-		time.Sleep(50 * time.Millisecond)
+		<-m.Started()
 		process, err := os.FindProcess(syscall.Getpid())
 		if err != nil {
 			t.Errorf("os.FindProcess error: %v", err)
@@ -264,4 +348,1030 @@ func testingSignal(t *testing.T, signal os.Signal) {
 	if atomic.LoadInt32(&count) != 2 {
 		t.Errorf("count error: %v", atomic.LoadInt32(&count))
 	}
+
+	var sigErr *SignalError
+	if !errors.As(m.ShutdownCause(), &sigErr) || sigErr.Signal != signal {
+		t.Errorf("shutdown cause error: %v", m.ShutdownCause())
+	}
+}
+
+func TestJobDurations(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddNamedRunningJob("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	m.AddNamedShutdownJob("flush", func() error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		m.doGracefulShutdown()
+	}()
+
+	<-m.Done()
+
+	durations := m.JobDurations()
+	if len(durations) != 2 {
+		t.Fatalf("durations count error: %d", len(durations))
+	}
+	if durations["flush"] < 20*time.Millisecond {
+		t.Errorf("flush duration error: %v", durations["flush"])
+	}
+	if durations["worker"] < 50*time.Millisecond {
+		t.Errorf("worker duration error: %v", durations["worker"])
+	}
+}
+
+func TestMaxConcurrentJobs(t *testing.T) {
+	setup()
+	var running, peak, started int32
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithMaxConcurrentJobs(2),
+	)
+
+	for i := 0; i < 5; i++ {
+		m.AddRunningJob(func(ctx context.Context) error {
+			atomic.AddInt32(&started, 1)
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			<-ctx.Done()
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		m.doGracefulShutdown()
+	}()
+
+	<-m.Done()
+
+	if atomic.LoadInt32(&peak) != 2 {
+		t.Errorf("peak error: %v", atomic.LoadInt32(&peak))
+	}
+	if atomic.LoadInt32(&started) != 2 {
+		t.Errorf("queued jobs should not start after shutdown: %v", atomic.LoadInt32(&started))
+	}
+}
+
+func TestMaxConcurrentJobsQueue(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithMaxConcurrentJobs(1),
+	)
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	before := runtime.NumGoroutine()
+	for i := 0; i < 1000; i++ {
+		m.AddRunningJob(func(ctx context.Context) error {
+			return nil
+		})
+	}
+	// queued jobs do not hold a goroutine
+	if n := runtime.NumGoroutine() - before; n > 100 {
+		t.Errorf("goroutines error: %d", n)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	select {
+	case <-m.WaitJob("running-job-1000"):
+	default:
+		t.Error("queued job should be done once skipped")
+	}
+}
+
+func TestWithTriggerChannel(t *testing.T) {
+	setup()
+	var count int32 = 0
+	trigger := make(chan struct{})
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithTriggerChannel(trigger),
+	)
+
+	m.AddShutdownJob(func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(trigger)
+	}()
+
+	<-m.Done()
+
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+}
+
+func TestAddRunningJobFunc(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddRunningJobFunc(func() error {
+		return errors.New("job error")
+	})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		m.doGracefulShutdown()
+	}()
+
+	<-m.Done()
+
+	if len(m.errors) != 1 {
+		t.Errorf("fail error count: %d", len(m.errors))
+	}
+}
+
+func countSignalHandlers() int {
+	buf := make([]byte, 1<<20)
+	return strings.Count(string(buf[:runtime.Stack(buf, true)]), "handleSignals")
+}
+
+func TestWithoutSignalHandler(t *testing.T) {
+	setup()
+	before := countSignalHandlers()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithoutSignalHandler(),
+	)
+	time.Sleep(10 * time.Millisecond)
+
+	if countSignalHandlers() > before {
+		t.Errorf("signal handler should not be started")
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestShutdownCause(t *testing.T) {
+	setup()
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewManager(
+		WithContext(ctx),
+		WithLogger(NewEmptyLogger()),
+	)
+
+	if err := m.ShutdownCause(); err != nil {
+		t.Errorf("cause should be nil before shutdown: %v", err)
+	}
+
+	var jobCause error
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		jobCause = context.Cause(ctx)
+		return nil
+	})
+
+	cancel()
+	<-m.Done()
+
+	if !errors.Is(jobCause, ErrParentContextDone) || !errors.Is(jobCause, context.Canceled) {
+		t.Errorf("job cause error: %v", jobCause)
+	}
+	if !errors.Is(m.ShutdownCause(), ErrParentContextDone) {
+		t.Errorf("shutdown cause error: %v", m.ShutdownCause())
+	}
+}
+
+func TestShutdownCauseExplicit(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if !errors.Is(m.ShutdownCause(), ErrShutdownRequested) {
+		t.Errorf("shutdown cause error: %v", m.ShutdownCause())
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	setup()
+	block := make(chan struct{})
+	defer close(block)
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(50*time.Millisecond),
+	)
+
+	// ignores its context and never finishes in time
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if !m.TimedOut() {
+		t.Error("shutdown should be timed out")
+	}
+	if len(m.Errors()) != 0 {
+		t.Errorf("fail error count: %d", len(m.Errors()))
+	}
+}
+
+func TestPendingJobs(t *testing.T) {
+	setup()
+	block := make(chan struct{})
+	defer close(block)
+	l := NewBufferLogger(10)
+	m := NewManager(
+		WithLogger(l),
+		WithShutdownTimeout(50*time.Millisecond),
+	)
+
+	m.AddNamedRunningJob("stuck", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	m.AddNamedRunningJob("quick", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	// waits for the running jobs, so it never starts either
+	m.AddNamedShutdownJob("flush", func() error {
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if got := m.PendingJobs(); !reflect.DeepEqual(got, []string{"flush", "stuck"}) {
+		t.Errorf("pending jobs error: %v", got)
+	}
+	logged := false
+	for _, msg := range l.Messages() {
+		if strings.HasPrefix(msg, "ERROR: shutdown timed out") &&
+			strings.HasSuffix(msg, "abandoning unfinished jobs: flush, stuck") {
+			logged = true
+		}
+	}
+	if !logged {
+		t.Errorf("pending jobs not logged: %v", l.Messages())
+	}
+}
+
+func TestSetShutdownTimeout(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(30*time.Millisecond),
+	)
+
+	m.AddShutdownJob(func() error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	// extend the live deadline before it expires
+	m.SetShutdownTimeout(time.Second)
+	<-m.Done()
+
+	if m.TimedOut() {
+		t.Error("shutdown should not be timed out")
+	}
+}
+
+func TestSetShutdownTimeoutShorten(t *testing.T) {
+	setup()
+	block := make(chan struct{})
+	defer close(block)
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddShutdownJob(func() error {
+		<-block
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	time.Sleep(20 * time.Millisecond)
+	m.SetShutdownTimeout(10 * time.Millisecond)
+	<-m.Done()
+
+	if !m.TimedOut() {
+		t.Error("shutdown should be timed out")
+	}
+}
+
+func TestWithShutdownDeadline(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		opts     []Option
+		timedOut bool
+	}{
+		{"deadline", []Option{WithShutdownDeadline(time.Now().Add(30 * time.Millisecond))}, true},
+		{"sooner timeout", []Option{
+			WithShutdownDeadline(time.Now().Add(time.Hour)),
+			WithShutdownTimeout(30 * time.Millisecond),
+		}, true},
+		{"later deadline", []Option{WithShutdownDeadline(time.Now().Add(time.Hour))}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setup()
+			m := NewManager(append(tc.opts, WithLogger(NewEmptyLogger()))...)
+
+			m.AddShutdownJob(func() error {
+				time.Sleep(100 * time.Millisecond)
+				return nil
+			})
+
+			m.DoGracefulShutdown()
+			<-m.Done()
+
+			if m.TimedOut() != tc.timedOut {
+				t.Errorf("timed out error: %v", m.TimedOut())
+			}
+		})
+	}
+}
+
+func TestShutdownWithinTimeout(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(time.Second),
+	)
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if m.TimedOut() {
+		t.Error("shutdown should not be timed out")
+	}
+}
+
+func TestWithOnJobStart(t *testing.T) {
+	setup()
+	started := make(chan string, 1)
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithOnJobStart(func(name string) {
+			started <- name
+		}),
+	)
+
+	m.AddNamedRunningJob("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	if name := <-started; name != "worker" {
+		t.Errorf("started job error: %v", name)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestContextDeadlineAsShutdownTimeout(t *testing.T) {
+	setup()
+	block := make(chan struct{})
+	defer close(block)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	m := NewManager(
+		WithContext(ctx),
+		WithLogger(NewEmptyLogger()),
+	)
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	start := time.Now()
+	<-m.Done()
+
+	if !m.TimedOut() {
+		t.Error("shutdown should be timed out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown took too long: %v", elapsed)
+	}
+}
+
+func TestContextDeadlineStartsShutdown(t *testing.T) {
+	setup()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	m := NewManager(
+		WithContext(ctx),
+		WithLogger(NewEmptyLogger()),
+	)
+
+	var ran int32
+	m.AddShutdownJob(func() error {
+		atomic.StoreInt32(&ran, 1)
+		return nil
+	})
+
+	<-m.Done()
+
+	// the deadline started the shutdown, it leaves no budget to enforce
+	if m.TimedOut() {
+		t.Error("shutdown should not be timed out")
+	}
+	if atomic.LoadInt32(&ran) != 1 {
+		t.Error("shutdown job should run")
+	}
+}
+
+func TestShutdownTimeoutWinsOverContextDeadline(t *testing.T) {
+	setup()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	m := NewManager(
+		WithContext(ctx),
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(time.Second),
+	)
+
+	m.AddShutdownJob(func() error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+
+	<-m.Done()
+
+	if m.TimedOut() {
+		t.Error("shutdown should not be timed out")
+	}
+}
+
+func TestAddCancelableJob(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	stopped := make(chan struct{})
+	scoped := make(chan error, 1)
+	cancel := m.AddCancelableJob(func(ctx context.Context) error {
+		scoped <- OnShutdown(ctx, func() error { return nil })
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	})
+	// the job context carries the job scope
+	if err := <-scoped; err != nil {
+		t.Errorf("job scope error: %v", err)
+	}
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	cancel()
+	<-stopped
+
+	if atomic.LoadInt32(&count) != 0 {
+		t.Errorf("other jobs should keep running")
+	}
+	if m.ShutdownContext().Err() != nil {
+		t.Errorf("manager should not be shut down")
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+}
+
+func TestAddShutdownJobWithErrors(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		return errors.New("running error")
+	})
+	m.AddShutdownJob(func() error {
+		return errors.New("shutdown error")
+	})
+
+	var snapshot []error
+	m.AddShutdownJobWithErrors(func(errs []error) error {
+		snapshot = errs
+		return nil
+	})
+
+	time.Sleep(20 * time.Millisecond)
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if len(snapshot) != 1 || snapshot[0].Error() != "running error" {
+		t.Errorf("snapshot error: %v", snapshot)
+	}
+	if len(m.Errors()) != 2 {
+		t.Errorf("fail error count: %d", len(m.Errors()))
+	}
+}
+
+func TestWithIgnoredErrors(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithIgnoredErrors(http.ErrServerClosed, context.Canceled),
+	)
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		return http.ErrServerClosed
+	})
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		return fmt.Errorf("serve: %w", ctx.Err())
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if len(m.Errors()) != 0 {
+		t.Errorf("errors should stay empty: %v", m.Errors())
+	}
+}
+
+func TestWithIgnoredErrorsKeepsOthers(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithIgnoredErrors(http.ErrServerClosed),
+	)
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		return errors.New("listen failed")
+	})
+	m.AddShutdownJob(func() error {
+		return http.ErrServerClosed
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if len(m.Errors()) != 2 {
+		t.Errorf("errors count error: %v", m.Errors())
+	}
+}
+
+func TestDoneContext(t *testing.T) {
+	setup()
+	errFlush := errors.New("flush failed")
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddShutdownJob(func() error {
+		return errFlush
+	})
+
+	ctx := m.DoneContext()
+	if ctx.Err() != nil {
+		t.Errorf("err before done: %v", ctx.Err())
+	}
+
+	m.DoGracefulShutdown()
+	<-ctx.Done()
+
+	if !errors.Is(ctx.Err(), errFlush) {
+		t.Errorf("err error: %v", ctx.Err())
+	}
+}
+
+func TestDoneContextWithoutErrors(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.DoGracefulShutdown()
+	ctx := m.DoneContext()
+	<-ctx.Done()
+
+	if ctx.Err() != context.Canceled {
+		t.Errorf("err error: %v", ctx.Err())
+	}
+}
+
+func TestAddShutdownJobWithBudget(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(time.Second),
+	)
+
+	var remaining time.Duration
+	m.AddShutdownJobWithBudget(func(d time.Duration) error {
+		remaining = d
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if remaining <= 0 || remaining > time.Second {
+		t.Errorf("remaining error: %s", remaining)
+	}
+}
+
+func TestAddShutdownJobWithBudgetNoDeadline(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	var remaining time.Duration
+	m.AddShutdownJobWithBudget(func(d time.Duration) error {
+		remaining = d
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if remaining != NoShutdownDeadline {
+		t.Errorf("remaining error: %s", remaining)
+	}
+}
+
+func TestWithPanicHandler(t *testing.T) {
+	setup()
+	errCrash := errors.New("worker crashed")
+	recovered := make(chan interface{}, 2)
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithPanicHandler(func(name string, r interface{}) error {
+			recovered <- r
+			if name == "worker" {
+				return errCrash
+			}
+			return nil
+		}),
+	)
+
+	m.AddNamedRunningJob("worker", func(ctx context.Context) error {
+		panic("boom")
+	})
+	m.AddNamedShutdownJob("flush", func() error {
+		panic("ignored")
+	})
+
+	if r := <-recovered; r != "boom" {
+		t.Errorf("recovered value error: %v", r)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	errs := m.Errors()
+	if len(errs) != 1 || !errors.Is(errs[0], errCrash) {
+		t.Errorf("errors error: %v", errs)
+	}
+}
+
+func TestWithShutdownOnPanic(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()), WithShutdownOnPanic())
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		panic("four error")
+	})
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	<-m.Done()
+
+	var perr *PanicError
+	if !errors.As(m.ShutdownCause(), &perr) || perr.Value != "four error" {
+		t.Errorf("shutdown cause error: %v", m.ShutdownCause())
+	}
+	if len(m.Errors()) != 1 {
+		t.Errorf("fail error count: %d", len(m.Errors()))
+	}
+}
+
+func TestWithRecoverDisabled(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()), WithRecover(false))
+
+	// runJob is called directly so the escaping panic can be caught here
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("panic should escape: %v", r)
+		}
+		m.DoGracefulShutdown()
+		<-m.Done()
+	}()
+	m.runJob("running", "worker", func() error {
+		panic("boom")
+	})
+	t.Error("unreachable")
+}
+
+type ctxKey struct{}
+
+func TestWithCancelOnParentDone(t *testing.T) {
+	setup()
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+	m := NewManager(
+		WithContext(ctx),
+		WithLogger(NewEmptyLogger()),
+		WithCancelOnParentDone(false),
+	)
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+
+	if m.ShutdownContext().Err() != nil {
+		t.Error("parent cancellation should not shut down the manager")
+	}
+	if v := m.ShutdownContext().Value(ctxKey{}); v != "value" {
+		t.Errorf("context value error: %v", v)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestWithStackDumpOnSignal(t *testing.T) {
+	setup()
+	l := NewBufferLogger(10)
+	m := NewManager(
+		WithLogger(l),
+		WithStackDumpOnSignal(syscall.SIGQUIT),
+	)
+	<-m.Started()
+
+	process, err := os.FindProcess(syscall.Getpid())
+	if err != nil {
+		t.Fatalf("os.FindProcess error: %v", err)
+	}
+	if err := process.Signal(syscall.SIGQUIT); err != nil {
+		t.Fatalf("process.Signal error: %v", err)
+	}
+
+	<-m.Done()
+
+	dumped := false
+	for _, msg := range l.Messages() {
+		if strings.HasPrefix(msg, "ERROR: goroutine stacks:") && strings.Contains(msg, "goroutine ") {
+			dumped = true
+		}
+	}
+	if !dumped {
+		t.Errorf("missing stack dump: %v", l.Messages())
+	}
+}
+
+func TestContext(t *testing.T) {
+	setup()
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	m := NewManager(
+		WithContext(ctx),
+		WithLogger(NewEmptyLogger()),
+	)
+
+	m.AddShutdownJob(func() error {
+		time.Sleep(20 * time.Millisecond)
+		if m.Context().Err() != nil {
+			t.Error("context should not be done before shutdown jobs return")
+		}
+		return nil
+	})
+
+	if v := m.Context().Value(ctxKey{}); v != "value" {
+		t.Errorf("context value error: %v", v)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Context().Done()
+
+	if !errors.Is(m.Context().Err(), context.Canceled) {
+		t.Errorf("context error: %v", m.Context().Err())
+	}
+}
+
+func TestWaitJob(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	release := make(chan struct{})
+	m.AddNamedRunningJob("migrate", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	m.AddNamedShutdownJob("flush", func() error {
+		return nil
+	})
+
+	select {
+	case <-m.WaitJob("migrate"):
+		t.Fatal("job should still be running")
+	case <-m.WaitJob("unknown"):
+	}
+
+	close(release)
+	<-m.WaitJob("migrate")
+
+	m.DoGracefulShutdown()
+	<-m.WaitJob("flush")
+	<-m.Done()
+}
+
+func testShutdownOrdering(t *testing.T, opts ...Option) []string {
+	setup()
+	m := NewManager(append(opts, WithLogger(NewEmptyLogger()))...)
+
+	var lock sync.Mutex
+	var order []string
+	record := func(event string) {
+		lock.Lock()
+		order = append(order, event)
+		lock.Unlock()
+	}
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		record("running")
+		return nil
+	})
+	m.AddShutdownJob(func() error {
+		record("shutdown")
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	return order
+}
+
+func TestShutdownJobsAfterRunningJobs(t *testing.T) {
+	order := testShutdownOrdering(t)
+	if len(order) != 2 || order[0] != "running" || order[1] != "shutdown" {
+		t.Errorf("order error: %v", order)
+	}
+}
+
+func TestShutdownJobsConcurrentWithDrain(t *testing.T) {
+	order := testShutdownOrdering(t, WithShutdownJobsConcurrentWithDrain())
+	if len(order) != 2 || order[0] != "shutdown" || order[1] != "running" {
+		t.Errorf("order error: %v", order)
+	}
+}
+
+func TestWithMaxErrors(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()), WithMaxErrors(2))
+
+	for i := 1; i <= 5; i++ {
+		i := i
+		m.AddShutdownJobToPhase(i, func() error {
+			return fmt.Errorf("error %d", i)
+		})
+	}
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	errs := m.Errors()
+	if len(errs) != 2 || errs[0].Error() != "error 4" || errs[1].Error() != "error 5" {
+		t.Errorf("errors error: %v", errs)
+	}
+	if m.DroppedErrorCount() != 3 {
+		t.Errorf("dropped error count: %d", m.DroppedErrorCount())
+	}
+}
+
+func TestWaitContext(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.WaitContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait context error: %v", err)
+	}
+
+	m.DoGracefulShutdown()
+	if err := m.WaitContext(context.Background()); err != nil {
+		t.Errorf("wait context error: %v", err)
+	}
+	// Done is still closed for other waiters
+	<-m.Done()
+}
+
+func TestDoneAfterShutdownJobs(t *testing.T) {
+	setup()
+	var finished int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddShutdownJob(func() error {
+		time.Sleep(50 * time.Millisecond)
+		atomic.StoreInt32(&finished, 1)
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&finished) != 1 {
+		t.Error("done closed before the shutdown job completed")
+	}
+}
+
+func TestShutdownAfter(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	start := time.Now()
+	m.ShutdownAfter(30 * time.Millisecond)
+	<-m.Done()
+
+	if time.Since(start) < 30*time.Millisecond {
+		t.Error("shutdown started too early")
+	}
+	if !errors.Is(m.ShutdownCause(), ErrShutdownAfter) {
+		t.Errorf("shutdown cause error: %v", m.ShutdownCause())
+	}
+}
+
+func TestShutdownAfterEarlierShutdown(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	before := runtime.NumGoroutine()
+
+	m.ShutdownAfter(time.Hour)
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if !errors.Is(m.ShutdownCause(), ErrShutdownRequested) {
+		t.Errorf("shutdown cause error: %v", m.ShutdownCause())
+	}
+	// the timer goroutine exits with the shutdown
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if runtime.NumGoroutine() > before {
+		t.Errorf("goroutines leaked: %d > %d", runtime.NumGoroutine(), before)
+	}
+}
+
+func TestWithSignalBuffer(t *testing.T) {
+	if o := newOptions(); o.signalBuffer != 1 {
+		t.Errorf("default signal buffer error: %d", o.signalBuffer)
+	}
+	if o := newOptions(WithSignalBuffer(4)); o.signalBuffer != 4 {
+		t.Errorf("signal buffer error: %d", o.signalBuffer)
+	}
+	if o := newOptions(WithSignalBuffer(0)); o.signalBuffer != 1 {
+		t.Errorf("signal buffer error: %d", o.signalBuffer)
+	}
+}
+
+func TestDoGracefulShutdownSync(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddShutdownJob(func() error {
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	m.doGracefulShutdownSync()
+
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
 }
@@ -0,0 +1,55 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoJobContext is returned by OnShutdown when ctx was not passed to a
+// running job by the manager
+var ErrNoJobContext = errors.New("graceful: context does not belong to a running job")
+
+// OnShutdown registers f as a shutdown job on behalf of the running job
+// owning ctx, keeping its setup and teardown together:
+//
+//	m.AddRunningJob(func(ctx context.Context) error {
+//		conn, err := dial()
+//		if err != nil {
+//			return err
+//		}
+//		_ = graceful.OnShutdown(ctx, conn.Close)
+//		...
+//	})
+//
+// f is added like AddShutdownJob at the time of the call, named after the
+// job, so it runs in phase 0 alongside the shutdown jobs added explicitly,
+// with no ordering between them. Called once the shutdown started, f runs
+// as a final job, after every shutdown job.
+func OnShutdown(ctx context.Context, f ShtdownJob) error {
+	scope, ok := ctx.Value(jobKey{}).(jobScope)
+	if !ok {
+		return ErrNoJobContext
+	}
+	scope.manager.addJobCleanup(scope.name, f)
+	return nil
+}
+
+// addJobCleanup adds f as a shutdown job, or as a final job once the
+// shutdown jobs were collected
+func (g *Manager) addJobCleanup(name string, f ShtdownJob) {
+	g.lock.Lock()
+	started := !g.shutdownStart.IsZero()
+	if !started {
+		g.cleanupJobCount++
+		g.appendShutdownJob(shutdownJob{
+			name: fmt.Sprintf("%s-cleanup-%d", name, g.cleanupJobCount),
+			fn:   f,
+		})
+	}
+	g.lock.Unlock()
+
+	if started {
+		g.AddFinalJob(f)
+	}
+}
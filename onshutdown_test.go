@@ -0,0 +1,50 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOnShutdown(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	var closed, late int32
+	registered := make(chan error, 1)
+	m.AddNamedRunningJob("conn", func(ctx context.Context) error {
+		registered <- OnShutdown(ctx, func() error {
+			atomic.AddInt32(&closed, 1)
+			return errors.New("close failed")
+		})
+		<-ctx.Done()
+		// registered once the shutdown started, runs as a final job
+		return OnShutdown(ctx, func() error {
+			atomic.AddInt32(&late, 1)
+			return nil
+		})
+	})
+
+	if err := <-registered; err != nil {
+		t.Fatalf("register error: %v", err)
+	}
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&closed) != 1 || atomic.LoadInt32(&late) != 1 {
+		t.Errorf("cleanup error: closed=%d late=%d", closed, late)
+	}
+	if _, ok := m.JobDurations()["conn-cleanup-1"]; !ok {
+		t.Errorf("cleanup job name error: %v", m.JobDurations())
+	}
+	if len(m.Errors()) != 1 {
+		t.Errorf("errors count error: %d", len(m.Errors()))
+	}
+}
+
+func TestOnShutdownWithoutJob(t *testing.T) {
+	if err := OnShutdown(context.Background(), func() error { return nil }); !errors.Is(err, ErrNoJobContext) {
+		t.Errorf("error: %v", err)
+	}
+}
@@ -1,6 +1,11 @@
 package graceful
 
-import "context"
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
 
 // Option interface for configuration.
 type Option interface {
@@ -17,8 +22,39 @@ func (f OptionFunc) Apply(option *Options) {
 
 // Options for graceful shutdown
 type Options struct {
-	ctx    context.Context
-	logger Logger
+	ctx                     context.Context
+	logger                  Logger
+	maxConcurrentJobs       int
+	triggerCh               <-chan struct{}
+	disableSignalHandler    bool
+	shutdownTimeout         time.Duration
+	onJobStart              func(name string)
+	continueOnShutdownPanic bool
+	panicHandler            func(name string, recovered interface{}) error
+	cancelOnParentDone      bool
+	stackDumpSignal         os.Signal
+	shutdownJobsWithDrain   bool
+	summaryFormatter        func(Stats) string
+	signalActions           map[os.Signal]Action
+	maxErrors               int
+	readyFile               string
+	recoverPanics           bool
+	dedupShutdownJobs       bool
+	dedupPolicy             DedupPolicy
+	shutdownJobsOnForceExit bool
+	shutdownOnPanic         bool
+	drainPollInterval       time.Duration
+	signalBuffer            int
+	shutdownDeadline        time.Time
+	signalLogFormat         func(sig os.Signal, pid int) string
+	errorLogFile            string
+	shutdownOnReadyTimeout  bool
+	shutdownConcurrency     int
+	progressInterval        time.Duration
+	pidFunc                 func() string
+	ignoredErrors           []error
+	priorityCutoff          time.Duration
+	minPriority             int
 }
 
 // WithContext custom context
@@ -35,10 +71,298 @@ func WithLogger(logger Logger) Option {
 	})
 }
 
+// WithSlogLogger custom logger using an existing *slog.Logger.
+// It is the same as WithLogger(NewSlogLogger(WithSlog(l))).
+func WithSlogLogger(l *slog.Logger) Option {
+	return WithLogger(NewSlogLogger(WithSlog(l)))
+}
+
+// WithMaxConcurrentJobs limits how many running jobs execute at the same time.
+// Extra jobs are queued until a slot frees up, without a goroutine of their
+// own, and queued jobs are dropped once shutdown starts. Zero or a negative
+// value means no limit.
+func WithMaxConcurrentJobs(n int) Option {
+	return OptionFunc(func(o *Options) {
+		o.maxConcurrentJobs = n
+	})
+}
+
+// WithTriggerChannel shuts down the manager when ch receives a value or is closed
+func WithTriggerChannel(ch <-chan struct{}) Option {
+	return OptionFunc(func(o *Options) {
+		o.triggerCh = ch
+	})
+}
+
+// WithoutSignalHandler disables the built-in signal handler.
+// Shutdown is then only triggered by DoGracefulShutdown,
+// the background context or a trigger channel.
+func WithoutSignalHandler() Option {
+	return OptionFunc(func(o *Options) {
+		o.disableSignalHandler = true
+	})
+}
+
+// WithShutdownTimeout bounds how long the shutdown waits for jobs to return.
+// Jobs still running when it expires are abandoned and Done() is closed.
+// Without it, the deadline of the context given to WithContext bounds a
+// shutdown started before that deadline, otherwise the shutdown waits forever.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(o *Options) {
+		o.shutdownTimeout = timeout
+	})
+}
+
+// WithOnJobStart sets a callback invoked in the job goroutine
+// right before each running job starts.
+func WithOnJobStart(f func(name string)) Option {
+	return OptionFunc(func(o *Options) {
+		o.onJobStart = f
+	})
+}
+
+// WithContinueOnShutdownPanic sets whether the remaining shutdown jobs still
+// run after a shutdown job panicked. Defaults to true. When false, no shutdown
+// job starts after the panic: the phases following the one of the panicking
+// job are skipped, and so are the jobs of its own phase still waiting for a
+// WithShutdownConcurrency slot. Jobs already running are not interrupted.
+// Either way the panic is recorded as a *PanicError.
+func WithContinueOnShutdownPanic(continueOnPanic bool) Option {
+	return OptionFunc(func(o *Options) {
+		o.continueOnShutdownPanic = continueOnPanic
+	})
+}
+
+// WithPanicHandler sets a function called with the name of the job and the
+// recovered value whenever a running or shutdown job panics. The error it
+// returns, if any, is recorded instead of the default *PanicError.
+func WithPanicHandler(f func(name string, recovered interface{}) error) Option {
+	return OptionFunc(func(o *Options) {
+		o.panicHandler = f
+	})
+}
+
+// WithCancelOnParentDone sets whether closing the context given to WithContext
+// starts the shutdown. Defaults to true. When false, the context only
+// provides values to the job contexts, and its deadline no longer bounds
+// the shutdown either.
+func WithCancelOnParentDone(cancel bool) Option {
+	return OptionFunc(func(o *Options) {
+		o.cancelOnParentDone = cancel
+	})
+}
+
+// WithStackDumpOnSignal writes the stacks of all goroutines to the error
+// logger when sig is received, then starts the graceful shutdown.
+// Use it with syscall.SIGQUIT to find out why a process hangs.
+func WithStackDumpOnSignal(sig os.Signal) Option {
+	return OptionFunc(func(o *Options) {
+		o.stackDumpSignal = sig
+	})
+}
+
+// WithSignalAction sets what the manager does when sig is received.
+// By default SIGINT and SIGTERM shut down, SIGTSTP pauses and SIGCONT resumes.
+func WithSignalAction(sig os.Signal, action Action) Option {
+	return OptionFunc(func(o *Options) {
+		if o.signalActions == nil {
+			o.signalActions = make(map[os.Signal]Action)
+		}
+		o.signalActions[sig] = action
+	})
+}
+
+// WithShutdownJobsConcurrentWithDrain starts the shutdown jobs as soon as the
+// shutdown starts, while running jobs are still returning. By default the
+// shutdown jobs only start once every running job returned.
+func WithShutdownJobsConcurrentWithDrain() Option {
+	return OptionFunc(func(o *Options) {
+		o.shutdownJobsWithDrain = true
+	})
+}
+
+// WithShutdownConcurrency caps how many shutdown jobs of the same phase run
+// at once. One runs them sequentially in the order they were added. By
+// default, or when n is not positive, every job of a phase starts at once.
+func WithShutdownConcurrency(n int) Option {
+	return OptionFunc(func(o *Options) {
+		o.shutdownConcurrency = n
+	})
+}
+
+// WithPriorityCutoff skips the jobs added with AddShutdownJobWithPriority
+// whose priority is below minPriority when less than margin is left before
+// the shutdown deadline as they are about to start. Without a deadline no
+// job is skipped. Skipped jobs record an error wrapping ErrJobSkipped.
+func WithPriorityCutoff(margin time.Duration, minPriority int) Option {
+	return OptionFunc(func(o *Options) {
+		o.priorityCutoff = margin
+		o.minPriority = minPriority
+	})
+}
+
+// WithIgnoredErrors treats errors returned by running jobs that match one
+// of errs, according to errors.Is, as success: they are neither added to
+// Errors() nor reported as the job error. Use it for errors expected on
+// shutdown, such as http.ErrServerClosed or context.Canceled. Panics and
+// errors of shutdown jobs are always recorded.
+func WithIgnoredErrors(errs ...error) Option {
+	return OptionFunc(func(o *Options) {
+		o.ignoredErrors = append(o.ignoredErrors, errs...)
+	})
+}
+
+// WithMaxErrors keeps only the n most recent errors. Older errors are dropped
+// and counted by DroppedErrorCount. Zero, the default, keeps every error.
+func WithMaxErrors(n int) Option {
+	return OptionFunc(func(o *Options) {
+		o.maxErrors = n
+	})
+}
+
+// WithReadyFile creates an empty file at path the first time WaitReady
+// returns nil and removes it once Drain or the shutdown starts. Errors are
+// logged. Use it for orchestrators that probe a file instead of HTTP.
+func WithReadyFile(path string) Option {
+	return OptionFunc(func(o *Options) {
+		o.readyFile = path
+	})
+}
+
+// WithErrorLogFile appends the errors collected by the manager, as returned
+// by Errors, to the file at path once the shutdown completed. Each line holds
+// the time the error was recorded, the job name when one is known and the
+// error. Nothing is written without errors. Write failures are logged. It
+// keeps a record when stdout and stderr of a container are lost.
+func WithErrorLogFile(path string) Option {
+	return OptionFunc(func(o *Options) {
+		o.errorLogFile = path
+	})
+}
+
+// WithRecover controls whether panics in running and shutdown jobs are
+// recovered, the default. When disabled a panicking job crashes the process
+// with its full stack, as Go normally would, which helps while debugging.
+func WithRecover(enabled bool) Option {
+	return OptionFunc(func(o *Options) {
+		o.recoverPanics = enabled
+	})
+}
+
+// WithDedupShutdownJobs handles a shutdown job registered with the name of an
+// existing one according to policy, instead of running both. It prevents
+// double-close bugs when the same cleanup is added from several places.
+func WithDedupShutdownJobs(policy DedupPolicy) Option {
+	return OptionFunc(func(o *Options) {
+		o.dedupShutdownJobs = true
+		o.dedupPolicy = policy
+	})
+}
+
+// WithRunShutdownJobsBeforeForceExit makes a best effort pass over the shutdown
+// jobs that have not started yet when a second shutdown signal forces the exit.
+// The pass is bounded by one second, then the process exits anyway.
+func WithRunShutdownJobsBeforeForceExit() Option {
+	return OptionFunc(func(o *Options) {
+		o.shutdownJobsOnForceExit = true
+	})
+}
+
+// WithShutdownOnPanic starts the graceful shutdown when any running job
+// panics, after its stack is logged. The panic is the shutdown cause.
+// A panicked worker often leaves the process in a bad state, so draining
+// is safer than carrying on half broken.
+func WithShutdownOnPanic() Option {
+	return OptionFunc(func(o *Options) {
+		o.shutdownOnPanic = true
+	})
+}
+
+// WithShutdownOnReadyTimeout starts the graceful shutdown when a job added
+// with AddNamedRunningJobReadyTimeout is not ready in time, so a stuck
+// initialization fails fast instead of leaving the process not ready.
+func WithShutdownOnReadyTimeout() Option {
+	return OptionFunc(func(o *Options) {
+		o.shutdownOnReadyTimeout = true
+	})
+}
+
+// WithProgressInterval logs "still draining: N jobs remaining (M elapsed)"
+// every d while the shutdown waits for running jobs, so operators watching
+// the logs see a long drain is progressing. Disabled by default.
+func WithProgressInterval(d time.Duration) Option {
+	return OptionFunc(func(o *Options) {
+		o.progressInterval = d
+	})
+}
+
+// WithDrainPollInterval sets how often the drain waiters are polled during
+// the shutdown. Defaults to 100ms.
+func WithDrainPollInterval(d time.Duration) Option {
+	return OptionFunc(func(o *Options) {
+		o.drainPollInterval = d
+	})
+}
+
+// WithSignalBuffer sets the capacity of the channel receiving signals.
+// Signals arriving while it is full are dropped, so raise it to make sure
+// a quick second Ctrl+C still forces the exit. Defaults to 1.
+func WithSignalBuffer(n int) Option {
+	return OptionFunc(func(o *Options) {
+		if n < 1 {
+			n = 1
+		}
+		o.signalBuffer = n
+	})
+}
+
+// WithShutdownDeadline bounds the shutdown by an absolute time, such as the
+// end of a maintenance window. With WithShutdownTimeout too, the sooner of
+// the two applies. A deadline already passed when the shutdown starts times
+// out right away. Check TimedOut() after Done() is closed.
+func WithShutdownDeadline(t time.Time) Option {
+	return OptionFunc(func(o *Options) {
+		o.shutdownDeadline = t
+	})
+}
+
+// WithSignalLogFormat customizes the line logged when a signal starts the
+// shutdown, by default "PID 42. Received SIGTERM. Shutting down...".
+// f decides whether to include the pid, which is always the process ID
+// even with WithPIDFunc.
+func WithSignalLogFormat(f func(sig os.Signal, pid int) string) Option {
+	return OptionFunc(func(o *Options) {
+		o.signalLogFormat = f
+	})
+}
+
+// WithPIDFunc overrides the process identity logged with signals and
+// shutdown triggers, the process ID by default. Use it where the PID inside
+// the namespace is not useful, e.g. to log the container ID.
+func WithPIDFunc(f func() string) Option {
+	return OptionFunc(func(o *Options) {
+		o.pidFunc = f
+	})
+}
+
+// WithSummaryFormatter customizes the line logged once the shutdown finished.
+// It goes to the error logger when errors were collected, else to the info logger.
+func WithSummaryFormatter(f func(Stats) string) Option {
+	return OptionFunc(func(o *Options) {
+		o.summaryFormatter = f
+	})
+}
+
 func newOptions(opts ...Option) Options {
 	defaultOpts := Options{
-		ctx:    context.Background(),
-		logger: NewLogger(),
+		ctx:                     context.Background(),
+		logger:                  NewLogger(),
+		continueOnShutdownPanic: true,
+		cancelOnParentDone:      true,
+		summaryFormatter:        defaultSummary,
+		recoverPanics:           true,
+		drainPollInterval:       100 * time.Millisecond,
+		signalBuffer:            1,
 	}
 
 	// Loop through each option
@@ -0,0 +1,32 @@
+package graceful
+
+import "fmt"
+
+// PanicError is the error recorded when a job panics. Panic values of any
+// type are normalized to it, so recovered panics show up in Errors() like
+// returned errors do.
+type PanicError struct {
+	// Kind is "running", "shutdown", "final", "background" for Manager.Go
+	// or "group" for Group.Go
+	Kind string
+	// Job is the name of the panicking job, empty for Manager.Go
+	Job string
+	// Value is the value passed to panic
+	Value interface{}
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	if e.Job == "" {
+		return fmt.Sprintf("panic in %s job: %v", e.Kind, e.Value)
+	}
+	return fmt.Sprintf("panic in %s job %q: %v", e.Kind, e.Job, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is and
+// errors.As see through a panic(err).
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
@@ -0,0 +1,56 @@
+package graceful
+
+import "context"
+
+// closedChan returns a closed channel
+func closedChan() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+// Pause asks the jobs to stop processing until Resume is called. Only jobs
+// calling WaitResumed take part. SIGTSTP pauses by default.
+func (g *Manager) Pause() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.paused {
+		return
+	}
+	g.paused = true
+	g.resumed = make(chan struct{})
+}
+
+// Resume releases the jobs blocked in WaitResumed. SIGCONT resumes by default.
+func (g *Manager) Resume() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if !g.paused {
+		return
+	}
+	g.paused = false
+	close(g.resumed)
+}
+
+// Paused reports whether the jobs are paused.
+func (g *Manager) Paused() bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.paused
+}
+
+// WaitResumed blocks while the jobs are paused, or until ctx is done. Jobs
+// opt in to pausing by calling it between units of work. It returns ctx.Err()
+// if ctx is done first, so a paused job still stops on shutdown.
+func (g *Manager) WaitResumed(ctx context.Context) error {
+	g.lock.RLock()
+	resumed := g.resumed
+	g.lock.RUnlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
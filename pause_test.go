@@ -0,0 +1,63 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPauseResume(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddRunningJob(func(ctx context.Context) error {
+		for {
+			if err := m.WaitResumed(ctx); err != nil {
+				return nil
+			}
+			atomic.AddInt32(&count, 1)
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	m.Pause()
+	if !m.Paused() {
+		t.Error("manager should be paused")
+	}
+	time.Sleep(10 * time.Millisecond)
+	paused := atomic.LoadInt32(&count)
+	time.Sleep(30 * time.Millisecond)
+	if atomic.LoadInt32(&count) != paused {
+		t.Errorf("job should be paused: %d != %d", atomic.LoadInt32(&count), paused)
+	}
+
+	m.Resume()
+	time.Sleep(30 * time.Millisecond)
+	if atomic.LoadInt32(&count) == paused {
+		t.Error("job should be resumed")
+	}
+
+	// a paused job still stops on shutdown
+	m.Pause()
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestWaitResumedContext(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	m.Pause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.WaitResumed(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait resumed error: %v", err)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+// +build !windows
+
+package graceful
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestPauseSignals(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	<-m.Started()
+
+	sendSignal(t, syscall.SIGTSTP)
+	for i := 0; i < 100 && !m.Paused(); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if !m.Paused() {
+		t.Error("SIGTSTP should pause the jobs")
+	}
+
+	sendSignal(t, syscall.SIGCONT)
+	for i := 0; i < 100 && m.Paused(); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if m.Paused() {
+		t.Error("SIGCONT should resume the jobs")
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
@@ -0,0 +1,127 @@
+package graceful
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AddShutdownJobToPhase add shutdown task to the given phase.
+// Phases run in ascending order: all jobs of a phase run concurrently and the
+// next phase starts only after every job of the previous one has returned.
+// Jobs added with AddShutdownJob belong to phase 0. Phases have no budget of
+// their own, they all share the time available to the whole shutdown.
+func (g *Manager) AddShutdownJobToPhase(phase int, f ShtdownJob) {
+	g.addShutdownJob(shutdownJob{name: g.nextShutdownJobName(), phase: phase, fn: f})
+}
+
+// ShutdownPlan returns the registered shutdown jobs in the order they will
+// run, without running them. Jobs outside phase 0 are followed by their phase,
+// jobs added with AddShutdownJobWithPriority by their priority instead, e.g.
+// "flush (priority 10)". Jobs of the same phase run concurrently, they are
+// listed in the order they were added. Final jobs are not included.
+func (g *Manager) ShutdownPlan() []string {
+	g.lock.RLock()
+	jobs := make([]shutdownJob, len(g.runAtShutdown))
+	copy(jobs, g.runAtShutdown)
+	g.lock.RUnlock()
+
+	sortByPhase(jobs)
+	plan := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		switch {
+		case job.priority != nil:
+			plan = append(plan, fmt.Sprintf("%s (priority %d)", job.name, *job.priority))
+		case job.phase != 0:
+			plan = append(plan, fmt.Sprintf("%s (phase %d)", job.name, job.phase))
+		default:
+			plan = append(plan, job.name)
+		}
+	}
+	return plan
+}
+
+// sortByPhase orders jobs by phase, keeping the order they were added in
+func sortByPhase(jobs []shutdownJob) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].phase < jobs[j].phase
+	})
+}
+
+// runShutdownPhases runs the shutdown jobs phase by phase
+func (g *Manager) runShutdownPhases(jobs []shutdownJob) {
+	sortByPhase(jobs)
+
+	// bounds the jobs of a phase running at once, see WithShutdownConcurrency
+	var sem chan struct{}
+	if g.shutdownConcurrency > 0 {
+		sem = make(chan struct{}, g.shutdownConcurrency)
+	}
+
+	// skipRemaining closes the jobs not started yet once a panic aborted
+	// the shutdown, see WithContinueOnShutdownPanic
+	skipRemaining := func(i int) bool {
+		if !g.isShutdownAborted() {
+			return false
+		}
+		g.log().Errorf("shutdown job panicked, skipping %d remaining shutdown jobs", len(jobs)-i)
+		for _, job := range jobs[i:] {
+			close(job.done)
+		}
+		return true
+	}
+
+	for start := 0; start < len(jobs); {
+		if skipRemaining(start) {
+			return
+		}
+
+		end := start
+		for end < len(jobs) && jobs[end].phase == jobs[start].phase {
+			end++
+		}
+
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			job := jobs[i]
+			if sem != nil {
+				sem <- struct{}{}
+			}
+			if skipRemaining(i) {
+				if sem != nil {
+					<-sem
+				}
+				wg.Wait()
+				return
+			}
+			if g.skipLowPriority(job) {
+				if sem != nil {
+					<-sem
+				}
+				close(job.done)
+				continue
+			}
+			wg.Add(1)
+			go func(job shutdownJob) {
+				defer wg.Done()
+				defer close(job.done)
+				if sem != nil {
+					defer func() { <-sem }()
+				}
+				if job.started.CompareAndSwap(false, true) {
+					g.doShutdownJob(job.name, job.fn)
+				}
+			}(job)
+		}
+		wg.Wait()
+
+		start = end
+	}
+}
+
+// isShutdownAborted reports whether a shutdown job panic aborted the shutdown sequence
+func (g *Manager) isShutdownAborted() bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.shutdownAborted
+}
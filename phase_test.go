@@ -0,0 +1,207 @@
+package graceful
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdownPhases(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	var lock sync.Mutex
+	var order []int
+	record := func(phase int, delay time.Duration) ShtdownJob {
+		return func() error {
+			time.Sleep(delay)
+			lock.Lock()
+			order = append(order, phase)
+			lock.Unlock()
+			return nil
+		}
+	}
+
+	m.AddShutdownJobToPhase(3, record(3, 0))
+	m.AddShutdownJobToPhase(1, record(1, 30*time.Millisecond))
+	m.AddShutdownJobToPhase(2, record(2, 0))
+	m.AddShutdownJobToPhase(1, record(1, 10*time.Millisecond))
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if want := []int{1, 1, 2, 3}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order error: %v", order)
+	}
+}
+
+func testShutdownPanic(t *testing.T, continueOnPanic bool) int32 {
+	setup()
+	var count int32 = 0
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithContinueOnShutdownPanic(continueOnPanic),
+	)
+
+	m.AddShutdownJobToPhase(1, func() error {
+		panic("flush failed")
+	})
+	m.AddShutdownJobToPhase(1, func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+	m.AddShutdownJobToPhase(2, func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	errs := m.Errors()
+	var panicErr *PanicError
+	if len(errs) != 1 || !errors.As(errs[0], &panicErr) {
+		t.Fatalf("panic error: %v", errs)
+	}
+	if panicErr.Value != "flush failed" || len(panicErr.Stack) == 0 {
+		t.Errorf("panic error content: %+v", panicErr)
+	}
+
+	return atomic.LoadInt32(&count)
+}
+
+func TestContinueOnShutdownPanic(t *testing.T) {
+	if count := testShutdownPanic(t, true); count != 2 {
+		t.Errorf("count error: %v", count)
+	}
+}
+
+func TestAbortOnShutdownPanic(t *testing.T) {
+	// jobs of the same phase already started, later phases are skipped
+	if count := testShutdownPanic(t, false); count != 1 {
+		t.Errorf("count error: %v", count)
+	}
+}
+
+func TestAbortOnShutdownPanicConcurrency(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithContinueOnShutdownPanic(false),
+		WithShutdownConcurrency(1),
+	)
+
+	var count int32
+	m.AddShutdownJob(func() error {
+		panic("flush failed")
+	})
+	for i := 0; i < 2; i++ {
+		m.AddShutdownJob(func() error {
+			atomic.AddInt32(&count, 1)
+			return nil
+		})
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	// the jobs waiting for a slot never start
+	if count := atomic.LoadInt32(&count); count != 0 {
+		t.Errorf("count error: %v", count)
+	}
+}
+
+func TestWithShutdownConcurrency(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()), WithShutdownConcurrency(2))
+
+	var running, peak int32
+	for i := 0; i < 6; i++ {
+		m.AddShutdownJob(func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return errors.New("shutdown error")
+		})
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if p := atomic.LoadInt32(&peak); p != 2 {
+		t.Errorf("peak concurrency error: %d", p)
+	}
+	if len(m.Errors()) != 6 {
+		t.Errorf("errors count error: %d", len(m.Errors()))
+	}
+}
+
+func TestWithShutdownConcurrencySequential(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()), WithShutdownConcurrency(1))
+
+	var lock sync.Mutex
+	var order []int
+	for i := 0; i < 5; i++ {
+		i := i
+		m.AddShutdownJob(func() error {
+			lock.Lock()
+			order = append(order, i)
+			lock.Unlock()
+			return nil
+		})
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if want := []int{0, 1, 2, 3, 4}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order error: %v", order)
+	}
+}
+
+func TestShutdownPlan(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	var count int32 = 0
+	job := func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}
+	m.AddNamedShutdownJob("close-db", job)
+	m.AddShutdownJobToPhase(2, job)
+	m.AddShutdownJobWithPriority(10, job)
+	m.AddNamedShutdownJob("close-cache", job)
+
+	plan := m.ShutdownPlan()
+	want := []string{
+		"shutdown-job-2 (priority 10)",
+		"close-db",
+		"close-cache",
+		"shutdown-job-1 (phase 2)",
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("plan error: %v", plan)
+	}
+	if atomic.LoadInt32(&count) != 0 {
+		t.Error("the plan should not run any job")
+	}
+
+	plan[0] = "changed"
+	if m.ShutdownPlan()[0] != want[0] {
+		t.Error("the plan should be a copy")
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
@@ -0,0 +1,42 @@
+package graceful
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrJobSkipped is wrapped by the error recorded for a shutdown job skipped
+// because the deadline was too close, see WithPriorityCutoff
+var ErrJobSkipped = errors.New("graceful: shutdown job skipped")
+
+// AddShutdownJobWithPriority add shutdown task that runs before the jobs of
+// lower priority. A job of priority p belongs to phase -p, so priority jobs
+// run before the jobs added with AddShutdownJob when p is positive. Low
+// priority jobs can be skipped under time pressure with WithPriorityCutoff.
+func (g *Manager) AddShutdownJobWithPriority(priority int, f ShtdownJob) {
+	g.addShutdownJob(shutdownJob{
+		name:     g.nextShutdownJobName(),
+		phase:    -priority,
+		fn:       f,
+		priority: &priority,
+	})
+}
+
+// skipLowPriority reports whether job must be skipped, recording why
+func (g *Manager) skipLowPriority(job shutdownJob) bool {
+	if job.priority == nil || g.priorityCutoff <= 0 || *job.priority >= g.minPriority {
+		return false
+	}
+	remaining, ok := g.shutdownRemaining()
+	if !ok || remaining >= g.priorityCutoff {
+		return false
+	}
+
+	err := fmt.Errorf("%w: %q with priority %d, %s left",
+		ErrJobSkipped, job.name, *job.priority, remaining.Round(time.Millisecond))
+	g.log().Error(err)
+	g.recordError(job.name, err)
+	g.emit(JobSkipped{Name: job.name, Priority: *job.priority})
+	return true
+}
@@ -0,0 +1,81 @@
+package graceful
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddShutdownJobWithPriority(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	var lock sync.Mutex
+	var order []string
+	record := func(s string) ShtdownJob {
+		return func() error {
+			lock.Lock()
+			order = append(order, s)
+			lock.Unlock()
+			return nil
+		}
+	}
+
+	m.AddShutdownJob(record("default"))
+	m.AddShutdownJobWithPriority(1, record("analytics"))
+	m.AddShutdownJobWithPriority(10, record("payments"))
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if want := []string{"payments", "analytics", "default"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order error: %v", order)
+	}
+}
+
+func TestWithPriorityCutoff(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(time.Second),
+		WithPriorityCutoff(900*time.Millisecond, 5),
+	)
+	events := m.Events()
+
+	var ran []int
+	var lock sync.Mutex
+	job := func(priority int, delay time.Duration) {
+		m.AddShutdownJobWithPriority(priority, func() error {
+			time.Sleep(delay)
+			lock.Lock()
+			ran = append(ran, priority)
+			lock.Unlock()
+			return nil
+		})
+	}
+	job(10, 200*time.Millisecond)
+	job(5, 0)
+	job(1, 0)
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if want := []int{10, 5}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran error: %v", ran)
+	}
+	if errs := m.Errors(); len(errs) != 1 || !errors.Is(errs[0], ErrJobSkipped) {
+		t.Errorf("errors error: %v", errs)
+	}
+
+	skipped := 0
+	for e := range events {
+		if s, ok := e.(JobSkipped); ok && s.Priority == 1 {
+			skipped++
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("skipped events error: %d", skipped)
+	}
+}
@@ -0,0 +1,59 @@
+package graceful
+
+import "time"
+
+// logProgress logs how many running jobs are left every progress interval
+// until they all returned or the shutdown completed
+func (g *Manager) logProgress() {
+	if g.progressInterval <= 0 {
+		return
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		g.runningWaitGroup.Wait()
+		close(drained)
+	}()
+
+	ticker := time.NewTicker(g.progressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-drained:
+			return
+		case <-g.doneCtx.Done():
+			return
+		case <-ticker.C:
+			g.lock.RLock()
+			start := g.shutdownStart
+			g.lock.RUnlock()
+			g.log().Infof(
+				"still draining: %d jobs remaining (%s elapsed)",
+				g.pendingRunningJobs(), time.Since(start).Round(time.Millisecond),
+			)
+		}
+	}
+}
+
+// pendingRunningJobs returns the number of running jobs that did not return yet
+func (g *Manager) pendingRunningJobs() int {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	shutdownJobs := make(map[string]bool, len(g.runAtShutdown))
+	for _, job := range g.runAtShutdown {
+		shutdownJobs[job.name] = true
+	}
+	n := 0
+	for name, done := range g.jobDone {
+		if shutdownJobs[name] {
+			continue
+		}
+		select {
+		case <-done:
+		default:
+			n++
+		}
+	}
+	return n
+}
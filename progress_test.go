@@ -0,0 +1,45 @@
+package graceful
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithProgressInterval(t *testing.T) {
+	setup()
+	l := NewBufferLogger(50)
+	m := NewManager(WithLogger(l), WithProgressInterval(10*time.Millisecond))
+
+	m.AddNamedRunningJob("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+	m.AddShutdownJob(func() error {
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	count := func() int {
+		n := 0
+		for _, msg := range l.Messages() {
+			if strings.HasPrefix(msg, "INFO: still draining: 1 jobs remaining (") {
+				n++
+			}
+		}
+		return n
+	}
+	n := count()
+	if n == 0 {
+		t.Fatalf("progress not logged: %v", l.Messages())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if count() != n {
+		t.Error("progress should stop once the drain completed")
+	}
+}
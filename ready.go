@@ -0,0 +1,110 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrReadyTimeout is wrapped by the error recorded when a job added with
+// AddNamedRunningJobReadyTimeout is not ready in time
+var ErrReadyTimeout = errors.New("graceful: job not ready in time")
+
+// ReadyJob is a running task that calls ready once it is actually serving
+type ReadyJob func(ctx context.Context, ready func()) error
+
+// AddRunningJobReady add running task that reports when it is ready,
+// see WaitReady.
+func (g *Manager) AddRunningJobReady(f ReadyJob) {
+	g.AddNamedRunningJobReady(g.nextRunningJobName(), f)
+}
+
+// AddNamedRunningJobReady add named running task that reports when it is ready,
+// see WaitReady.
+func (g *Manager) AddNamedRunningJobReady(name string, f ReadyJob) {
+	g.addRunningJobReady(name, 0, f)
+}
+
+// AddNamedRunningJobReadyTimeout add named running task that must call ready,
+// or return, within timeout. Otherwise an error wrapping ErrReadyTimeout is recorded,
+// and the shutdown starts with it as the cause when WithShutdownOnReadyTimeout
+// is set. The job itself keeps running, a late ready still releases WaitReady.
+func (g *Manager) AddNamedRunningJobReadyTimeout(name string, timeout time.Duration, f ReadyJob) {
+	g.addRunningJobReady(name, timeout, f)
+}
+
+func (g *Manager) addRunningJobReady(name string, timeout time.Duration, f ReadyJob) {
+	readyCh := make(chan struct{})
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			close(readyCh)
+		})
+	}
+	ready := func() {
+		release()
+		g.markReady(name)
+	}
+
+	g.lock.Lock()
+	g.jobReady[name] = readyCh
+	g.lock.Unlock()
+
+	if timeout > 0 {
+		g.runningWaitGroup.Run(func() {
+			g.watchReady(name, readyCh, timeout)
+		})
+	}
+
+	// a job returning without calling ready, or never started,
+	// no longer holds up WaitReady
+	g.addRunningJob(name, func(ctx context.Context) error {
+		defer release()
+		return f(ctx, ready)
+	}, release)
+}
+
+// watchReady records an error when readyCh is not closed within timeout
+func (g *Manager) watchReady(name string, readyCh <-chan struct{}, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-readyCh:
+		return
+	case <-g.shutdownCtx.Done():
+		return
+	case <-timer.C:
+	}
+
+	err := fmt.Errorf("%w: %q after %s", ErrReadyTimeout, name, timeout)
+	g.log().Error(err)
+	g.recordError(name, err)
+	if g.shutdownOnReadyTimeout {
+		g.shutdownWithCause(err)
+	}
+}
+
+// WaitReady blocks until every job added with AddRunningJobReady so far
+// called ready or returned, or until ctx is done. Jobs added with
+// AddRunningJob are not waited for. It creates the WithReadyFile file on success.
+func (g *Manager) WaitReady(ctx context.Context) error {
+	g.lock.RLock()
+	pending := make([]chan struct{}, 0, len(g.jobReady))
+	for _, readyCh := range g.jobReady {
+		pending = append(pending, readyCh)
+	}
+	g.lock.RUnlock()
+
+	for _, readyCh := range pending {
+		select {
+		case <-readyCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	g.createReadyFile()
+	return nil
+}
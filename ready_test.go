@@ -0,0 +1,123 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWaitReady(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	listening := make(chan struct{})
+	m.AddRunningJobReady(func(ctx context.Context, ready func()) error {
+		<-listening
+		ready()
+		<-ctx.Done()
+		return nil
+	})
+	// never calls ready but returns, so it is excluded from the wait
+	m.AddRunningJobReady(func(ctx context.Context, ready func()) error {
+		return nil
+	})
+	// plain running jobs are not waited for
+	m.AddRunningJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.WaitReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait ready error: %v", err)
+	}
+
+	close(listening)
+	if err := m.WaitReady(context.Background()); err != nil {
+		t.Errorf("wait ready error: %v", err)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestWithReadyFile(t *testing.T) {
+	setup()
+	path := filepath.Join(t.TempDir(), "ready")
+	m := NewManager(WithLogger(NewEmptyLogger()), WithReadyFile(path))
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("ready file should not exist yet: %v", err)
+	}
+	if err := m.WaitReady(context.Background()); err != nil {
+		t.Fatalf("wait ready error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("ready file should exist: %v", err)
+	}
+
+	m.DoGracefulShutdown()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("ready file should be removed: %v", err)
+	}
+	<-m.Done()
+
+	// the manager no longer runs, so the file is not created again
+	_ = m.WaitReady(context.Background())
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("ready file should not be recreated: %v", err)
+	}
+}
+
+func TestReadyTimeout(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()), WithShutdownOnReadyTimeout())
+
+	m.AddNamedRunningJobReadyTimeout("db", 20*time.Millisecond, func(ctx context.Context, ready func()) error {
+		<-ctx.Done()
+		return nil
+	})
+	m.AddNamedRunningJobReadyTimeout("http", time.Second, func(ctx context.Context, ready func()) error {
+		ready()
+		<-ctx.Done()
+		return nil
+	})
+
+	select {
+	case <-m.Done():
+	case <-time.After(time.Second):
+		t.Fatal("ready timeout should start the shutdown")
+	}
+
+	if !errors.Is(m.ShutdownCause(), ErrReadyTimeout) {
+		t.Errorf("cause error: %v", m.ShutdownCause())
+	}
+	if errs := m.Errors(); len(errs) != 1 || errs[0].Error() != `graceful: job not ready in time: "db" after 20ms` {
+		t.Errorf("errors error: %v", errs)
+	}
+}
+
+func TestReadyTimeoutWithoutShutdown(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddNamedRunningJobReadyTimeout("db", 10*time.Millisecond, func(ctx context.Context, ready func()) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	if m.ShutdownCause() != nil {
+		t.Errorf("shutdown should not start: %v", m.ShutdownCause())
+	}
+	if errs := m.Errors(); len(errs) != 1 || !errors.Is(errs[0], ErrReadyTimeout) {
+		t.Errorf("errors error: %v", errs)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
@@ -0,0 +1,36 @@
+package graceful
+
+import "os"
+
+// createReadyFile writes the ready file unless the manager stopped running
+func (g *Manager) createReadyFile() {
+	if g.readyFile == "" {
+		return
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.state != stateRunning || g.readyFileCreated {
+		return
+	}
+	if err := os.WriteFile(g.readyFile, nil, 0o644); err != nil {
+		g.logger.Errorf("create ready file: %v", err)
+		return
+	}
+	g.readyFileCreated = true
+}
+
+// removeReadyFile deletes the ready file if the manager created it
+func (g *Manager) removeReadyFile() {
+	if g.readyFile == "" {
+		return
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if !g.readyFileCreated {
+		return
+	}
+	g.readyFileCreated = false
+	if err := os.Remove(g.readyFile); err != nil && !os.IsNotExist(err) {
+		g.logger.Errorf("remove ready file: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package graceful
+
+import "sync"
+
+// JobRegistrar is the job registration surface of the Manager.
+// Accept it instead of *Manager to test components with NoopManager.
+type JobRegistrar interface {
+	AddRunningJob(f RunningJob)
+	AddShutdownJob(f ShtdownJob)
+}
+
+var (
+	_ JobRegistrar = (*Manager)(nil)
+	_ JobRegistrar = (*NoopManager)(nil)
+)
+
+// NoopManager records job registrations without ever running them.
+type NoopManager struct {
+	lock         sync.Mutex
+	runningJobs  []RunningJob
+	shutdownJobs []ShtdownJob
+}
+
+// NewNoopManager creates an empty NoopManager.
+func NewNoopManager() *NoopManager {
+	return &NoopManager{}
+}
+
+// AddRunningJob records a running task
+func (m *NoopManager) AddRunningJob(f RunningJob) {
+	m.lock.Lock()
+	m.runningJobs = append(m.runningJobs, f)
+	m.lock.Unlock()
+}
+
+// AddShutdownJob records a shutdown task
+func (m *NoopManager) AddShutdownJob(f ShtdownJob) {
+	m.lock.Lock()
+	m.shutdownJobs = append(m.shutdownJobs, f)
+	m.lock.Unlock()
+}
+
+// RunningJobs returns the recorded running tasks
+func (m *NoopManager) RunningJobs() []RunningJob {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]RunningJob(nil), m.runningJobs...)
+}
+
+// ShutdownJobs returns the recorded shutdown tasks
+func (m *NoopManager) ShutdownJobs() []ShtdownJob {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]ShtdownJob(nil), m.shutdownJobs...)
+}
@@ -0,0 +1,40 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+)
+
+type component struct {
+	closed bool
+}
+
+func (c *component) register(r JobRegistrar) {
+	r.AddRunningJob(func(ctx context.Context) error {
+		return nil
+	})
+	r.AddShutdownJob(func() error {
+		c.closed = true
+		return nil
+	})
+}
+
+func TestNoopManager(t *testing.T) {
+	m := NewNoopManager()
+	c := &component{}
+	c.register(m)
+
+	if len(m.RunningJobs()) != 1 {
+		t.Errorf("running jobs count error: %d", len(m.RunningJobs()))
+	}
+	jobs := m.ShutdownJobs()
+	if len(jobs) != 1 {
+		t.Fatalf("shutdown jobs count error: %d", len(jobs))
+	}
+	if c.closed {
+		t.Error("jobs should not run on registration")
+	}
+	if err := jobs[0](); err != nil || !c.closed {
+		t.Errorf("shutdown job error: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package graceful
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// jobResult is the outcome of a finished job
+type jobResult struct {
+	err      error
+	panicked bool
+	duration time.Duration
+	finished time.Time
+}
+
+// JobReport describes a finished job in the error report
+type JobReport struct {
+	Name     string `json:"name"`
+	Error    string `json:"error,omitempty"`
+	Panicked bool   `json:"panicked"`
+	Duration string `json:"duration"`
+}
+
+// ErrorReport is the document produced by Manager.ErrorReport
+type ErrorReport struct {
+	TimedOut bool        `json:"timed_out"`
+	Jobs     []JobReport `json:"jobs"`
+}
+
+// ErrorReport returns a JSON document describing every finished job,
+// sorted by job name. Jobs without error have no error field.
+func (g *Manager) ErrorReport() ([]byte, error) {
+	g.lock.RLock()
+	report := ErrorReport{
+		TimedOut: g.timedOut,
+		Jobs:     make([]JobReport, 0, len(g.jobResults)),
+	}
+	for name, result := range g.jobResults {
+		job := JobReport{
+			Name:     name,
+			Panicked: result.panicked,
+			Duration: result.duration.String(),
+		}
+		if result.err != nil {
+			job.Error = result.err.Error()
+		}
+		report.Jobs = append(report.Jobs, job)
+	}
+	g.lock.RUnlock()
+
+	sort.Slice(report.Jobs, func(i, j int) bool {
+		return report.Jobs[i].Name < report.Jobs[j].Name
+	})
+
+	return json.Marshal(report)
+}
@@ -0,0 +1,58 @@
+package graceful
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestErrorReport(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddNamedRunningJob("worker", func(ctx context.Context) error {
+		return errors.New("worker failed")
+	})
+	m.AddNamedShutdownJob("flush", func() error {
+		panic("flush failed")
+	})
+	m.AddNamedShutdownJob("close", func() error {
+		return nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	data, err := m.ErrorReport()
+	if err != nil {
+		t.Fatalf("report error: %v", err)
+	}
+
+	var report ErrorReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(report.Jobs) != 3 {
+		t.Fatalf("jobs count error: %d", len(report.Jobs))
+	}
+
+	names := []string{"close", "flush", "worker"}
+	for i, job := range report.Jobs {
+		if job.Name != names[i] {
+			t.Errorf("job order error: %v", job.Name)
+		}
+		if job.Duration == "" {
+			t.Errorf("missing duration: %v", job.Name)
+		}
+	}
+	if report.Jobs[0].Error != "" || report.Jobs[0].Panicked {
+		t.Errorf("close job error: %+v", report.Jobs[0])
+	}
+	if report.Jobs[1].Error != `panic in shutdown job "flush": flush failed` || !report.Jobs[1].Panicked {
+		t.Errorf("flush job error: %+v", report.Jobs[1])
+	}
+	if report.Jobs[2].Error != "worker failed" || report.Jobs[2].Panicked {
+		t.Errorf("worker job error: %+v", report.Jobs[2])
+	}
+}
@@ -0,0 +1,36 @@
+// Package restart upgrades a running binary without downtime. On a signal the
+// process starts a new copy of itself that inherits the listening socket, then
+// drains through the graceful manager while the new process takes over.
+package restart
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+)
+
+// EnvListenerFD is the environment variable holding the file descriptor of
+// the listener inherited from the parent process.
+const EnvListenerFD = "GRACEFUL_LISTENER_FD"
+
+// ErrNotSupported is returned by Enable on platforms that cannot pass
+// file descriptors to a child process.
+var ErrNotSupported = errors.New("restart: not supported on this platform")
+
+// Listen returns the listener inherited from the parent process if
+// EnvListenerFD is set, else it listens on the network address.
+func Listen(network, addr string) (net.Listener, error) {
+	v := os.Getenv(EnvListenerFD)
+	if v == "" {
+		return net.Listen(network, addr)
+	}
+
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), "listener")
+	defer f.Close()
+	return net.FileListener(f)
+}
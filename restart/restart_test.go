@@ -0,0 +1,71 @@
+//go:build !windows
+// +build !windows
+
+package restart
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/appleboy/graceful"
+)
+
+func TestListenInherited(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	defer ln.Close()
+	f, err := ln.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("file error: %v", err)
+	}
+	t.Setenv(EnvListenerFD, strconv.Itoa(int(f.Fd())))
+
+	l, err := Listen("tcp", "ignored")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	defer l.Close()
+	if l.Addr().String() != ln.Addr().String() {
+		t.Errorf("inherited address error: %v", l.Addr())
+	}
+}
+
+func TestEnable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	defer ln.Close()
+
+	started := make(chan *exec.Cmd, 1)
+	startProcess = func(cmd *exec.Cmd) error {
+		started <- cmd
+		return nil
+	}
+
+	m := graceful.NewIndependentManager(graceful.WithLogger(graceful.NewEmptyLogger()))
+	if err := Enable(m, ln, syscall.SIGUSR2); err != nil {
+		t.Fatalf("enable error: %v", err)
+	}
+
+	process, err := os.FindProcess(syscall.Getpid())
+	if err != nil {
+		t.Fatalf("os.FindProcess error: %v", err)
+	}
+	if err := process.Signal(syscall.SIGUSR2); err != nil {
+		t.Fatalf("process.Signal error: %v", err)
+	}
+
+	cmd := <-started
+	<-m.Done()
+
+	if len(cmd.ExtraFiles) != 1 || cmd.Env[len(cmd.Env)-1] != EnvListenerFD+"=3" {
+		t.Errorf("command error: %v %v", cmd.ExtraFiles, cmd.Env[len(cmd.Env)-1])
+	}
+}
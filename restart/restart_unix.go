@@ -0,0 +1,84 @@
+//go:build !windows
+// +build !windows
+
+package restart
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/appleboy/graceful"
+)
+
+// startProcess starts the new process, replaced in tests
+var startProcess = func(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
+
+// filer is implemented by the listeners of the net package
+type filer interface {
+	File() (*os.File, error)
+}
+
+// Enable adds a running job to m that, on sig, starts the current binary
+// again with the same arguments and l as file descriptor 3, then starts the
+// graceful shutdown of m so the old process drains. The new process gets
+// its listener back with Listen. If the new process fails to start, the old
+// one keeps running and the error is logged.
+func Enable(m *graceful.Manager, l net.Listener, sig os.Signal) error {
+	if _, ok := l.(filer); !ok {
+		return fmt.Errorf("restart: listener %T cannot be passed to a child process", l)
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sig)
+
+	m.AddNamedRunningJob("restart", func(ctx context.Context) error {
+		defer signal.Stop(c)
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-c:
+				pid, err := spawn(l)
+				if err != nil {
+					graceful.LoggerFromContext(ctx).Errorf("restart failed: %v", err)
+					continue
+				}
+				graceful.LoggerFromContext(ctx).Infof("started new process %d, draining", pid)
+				m.DoGracefulShutdown()
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// spawn starts the current binary with l as file descriptor 3
+func spawn(l net.Listener) (int, error) {
+	f, err := l.(filer).File()
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = []*os.File{f}
+	cmd.Env = append(os.Environ(), EnvListenerFD+"=3")
+	if err := startProcess(cmd); err != nil {
+		return 0, err
+	}
+	if cmd.Process == nil {
+		return 0, nil
+	}
+	return cmd.Process.Pid, nil
+}
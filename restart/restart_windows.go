@@ -0,0 +1,13 @@
+package restart
+
+import (
+	"net"
+	"os"
+
+	"github.com/appleboy/graceful"
+)
+
+// Enable is not supported on Windows and returns ErrNotSupported.
+func Enable(m *graceful.Manager, l net.Listener, sig os.Signal) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,55 @@
+package graceful
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownJob is returned by RestartJob for a name no running job has
+	ErrUnknownJob = errors.New("graceful: unknown job")
+	// ErrJobRunning is returned by RestartJob while the job has not returned
+	ErrJobRunning = errors.New("graceful: job still running")
+	// ErrNotAccepting is returned by RestartJob once Drain or the shutdown started
+	ErrNotAccepting = errors.New("graceful: manager is no longer accepting running jobs")
+	// ErrNotRestartable is returned by RestartJob for a job that can only run once,
+	// such as one added with AddRunningJobResult
+	ErrNotRestartable = errors.New("graceful: job cannot be restarted")
+)
+
+// RestartJob relaunches the named running job once it returned, with the
+// same function and a fresh context derived from the shutdown context.
+// Health checks and cancel functions of the job apply to the new run. It
+// returns an error wrapping ErrUnknownJob, ErrJobRunning or
+// ErrNotRestartable, or ErrNotAccepting once Drain or the shutdown started.
+func (g *Manager) RestartJob(name string) error {
+	g.lock.Lock()
+	if g.state != stateRunning {
+		g.lock.Unlock()
+		return ErrNotAccepting
+	}
+	f, ok := g.jobFuncs[name]
+	if !ok {
+		g.lock.Unlock()
+		return fmt.Errorf("%w: %q", ErrUnknownJob, name)
+	}
+	if _, ok := g.oneShotJobs[name]; ok {
+		g.lock.Unlock()
+		return fmt.Errorf("%w: %q", ErrNotRestartable, name)
+	}
+	select {
+	case <-g.jobDone[name]:
+	default:
+		g.lock.Unlock()
+		return fmt.Errorf("%w: %q", ErrJobRunning, name)
+	}
+	// marks the job running right away, so a concurrent restart is
+	// rejected and WaitJob waits for the new run
+	done := make(chan struct{})
+	g.jobDone[name] = done
+	g.lock.Unlock()
+
+	g.log().Infof("restarting job %q", name)
+	g.startRunningJob(name, f, done, nil)
+	return nil
+}
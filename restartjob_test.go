@@ -0,0 +1,149 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRestartJob(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	var runs int32
+	exited := make(chan struct{}, 2)
+	m.AddNamedRunningJob("worker", func(ctx context.Context) error {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			exited <- struct{}{}
+			return errors.New("crashed")
+		}
+		<-ctx.Done()
+		return nil
+	})
+	<-exited
+	for i := 0; i < 100 && len(m.PendingJobs()) != 0; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := m.RestartJob("missing"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("unknown job error: %v", err)
+	}
+	if err := m.RestartJob("worker"); err != nil {
+		t.Fatalf("restart error: %v", err)
+	}
+	if err := m.RestartJob("worker"); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("running job error: %v", err)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&runs) != 2 {
+		t.Errorf("runs error: %d", runs)
+	}
+	if err := m.RestartJob("worker"); !errors.Is(err, ErrNotAccepting) {
+		t.Errorf("restart after shutdown error: %v", err)
+	}
+}
+
+func TestRestartWrappedJobs(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	// running-job-1
+	<-AddRunningJobResult(m, func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	<-m.WaitJob("running-job-1")
+	if err := m.RestartJob("running-job-1"); !errors.Is(err, ErrNotRestartable) {
+		t.Errorf("result job error: %v", err)
+	}
+
+	// running-job-2
+	var runs int32
+	started := make(chan struct{}, 2)
+	cancel := m.AddCancelableJob(func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		started <- struct{}{}
+		<-ctx.Done()
+		return nil
+	})
+	<-started
+	cancel()
+	<-m.WaitJob("running-job-2")
+	if err := m.RestartJob("running-job-2"); err != nil {
+		t.Fatalf("restart error: %v", err)
+	}
+	<-started
+	done := m.WaitJob("running-job-2")
+	select {
+	case <-done:
+		t.Fatal("restarted job should get a live context")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	<-done
+
+	errUnhealthy := errors.New("unhealthy")
+	exited := make(chan struct{}, 1)
+	m.AddRunningJobWithHealth("db", func(ctx context.Context) error {
+		if atomic.AddInt32(&runs, 1) == 3 {
+			exited <- struct{}{}
+			return nil
+		}
+		<-ctx.Done()
+		return nil
+	}, func() error {
+		return errUnhealthy
+	})
+	<-exited
+	<-m.WaitJob("db")
+	if err := m.RestartJob("db"); err != nil {
+		t.Fatalf("restart error: %v", err)
+	}
+	for i := 0; i < 100 && m.Healthy() == nil; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := m.Healthy(); !errors.Is(err, errUnhealthy) {
+		t.Errorf("health error: %v", err)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if len(m.Errors()) != 0 {
+		t.Errorf("unexpected errors: %v", m.Errors())
+	}
+}
+
+func TestDebugHandlerRestart(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	h := m.debugHandler(&debugOptions{token: "secret"})
+
+	m.AddNamedRunningJob("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	restart := func(job string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/graceful/restart?job="+job, nil)
+		r.Header.Set("Authorization", "Bearer secret")
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+	if code := restart("missing"); code != http.StatusNotFound {
+		t.Errorf("unknown job status error: %d", code)
+	}
+	if code := restart("worker"); code != http.StatusConflict {
+		t.Errorf("running job status error: %d", code)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
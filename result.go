@@ -0,0 +1,29 @@
+package graceful
+
+import "context"
+
+// AddRunningJobResult add running task that produces a result. Go methods
+// cannot have type parameters, so it takes the manager as first argument.
+// The result is delivered on the returned channel when f succeeds, errors
+// are recorded like any other running job. The channel is buffered so the
+// job never blocks on a missing receiver, and it is closed once the job
+// returns or if the job never runs. The job cannot be restarted.
+func AddRunningJobResult[T any](g *Manager, f func(context.Context) (T, error)) <-chan T {
+	ch := make(chan T, 1)
+	name := g.nextRunningJobName()
+	g.lock.Lock()
+	g.oneShotJobs[name] = struct{}{}
+	g.lock.Unlock()
+	g.addRunningJob(name, func(ctx context.Context) error {
+		defer close(ch)
+		result, err := f(ctx)
+		if err != nil {
+			return err
+		}
+		ch <- result
+		return nil
+	}, func() {
+		close(ch)
+	})
+	return ch
+}
@@ -0,0 +1,36 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAddRunningJobResult(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	total := AddRunningJobResult(m, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 42, nil
+	})
+	failed := AddRunningJobResult(m, func(ctx context.Context) (string, error) {
+		return "", errors.New("batch failed")
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if v, ok := <-total; !ok || v != 42 {
+		t.Errorf("result error: %v %v", v, ok)
+	}
+	if _, ok := <-total; ok {
+		t.Error("channel should be closed")
+	}
+	if v, ok := <-failed; ok {
+		t.Errorf("failed job should not send a result: %v", v)
+	}
+	if len(m.Errors()) != 1 {
+		t.Errorf("fail error count: %d", len(m.Errors()))
+	}
+}
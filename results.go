@@ -0,0 +1,32 @@
+package graceful
+
+// AddShutdownJobResult add named shutdown task that produces a result, such
+// as a flushed offset, readable with ShutdownResults once Done is closed.
+// The result is stored even when f returns an error. A later result with the
+// same name overwrites the previous one and a warning is logged.
+func (g *Manager) AddShutdownJobResult(name string, f func() (interface{}, error)) {
+	g.AddNamedShutdownJob(name, func() error {
+		result, err := f()
+		g.lock.Lock()
+		_, exists := g.shutdownResults[name]
+		g.shutdownResults[name] = result
+		g.lock.Unlock()
+		if exists {
+			g.log().Errorf("shutdown result %q overwritten", name)
+		}
+		return err
+	})
+}
+
+// ShutdownResults returns a copy of the results stored by the jobs added
+// with AddShutdownJobResult, keyed by job name.
+func (g *Manager) ShutdownResults() map[string]interface{} {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	results := make(map[string]interface{}, len(g.shutdownResults))
+	for name, result := range g.shutdownResults {
+		results[name] = result
+	}
+	return results
+}
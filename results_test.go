@@ -0,0 +1,60 @@
+package graceful
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestShutdownResults(t *testing.T) {
+	setup()
+	l := NewBufferLogger(20)
+	m := NewManager(WithLogger(l))
+
+	m.AddShutdownJobResult("offset", func() (interface{}, error) {
+		return int64(42), nil
+	})
+	m.AddShutdownJobResult("bytes", func() (interface{}, error) {
+		return 1024, errors.New("partial write")
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	results := m.ShutdownResults()
+	if len(results) != 2 || results["offset"] != int64(42) || results["bytes"] != 1024 {
+		t.Errorf("results error: %v", results)
+	}
+	if len(m.Errors()) != 1 {
+		t.Errorf("errors count error: %d", len(m.Errors()))
+	}
+}
+
+func TestShutdownResultsOverwrite(t *testing.T) {
+	setup()
+	l := NewBufferLogger(20)
+	m := NewManager(WithLogger(l))
+
+	m.AddShutdownJobResult("offset", func() (interface{}, error) {
+		return 1, nil
+	})
+	m.AddShutdownJobResult("offset", func() (interface{}, error) {
+		return 2, nil
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	// both jobs run concurrently, either result may win
+	if results := m.ShutdownResults(); len(results) != 1 || (results["offset"] != 1 && results["offset"] != 2) {
+		t.Errorf("results error: %v", results)
+	}
+	found := false
+	for _, msg := range l.Messages() {
+		if msg == `ERROR: shutdown result "offset" overwritten` {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("overwrite not logged: %v", l.Messages())
+	}
+}
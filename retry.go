@@ -0,0 +1,29 @@
+package graceful
+
+import "time"
+
+// AddShutdownJobWithRetry add shutdown task that is retried when it returns
+// an error, up to attempts calls in total with backoff between them. It stops
+// early when the next attempt would start after the shutdown deadline.
+// Only the error of the last attempt is recorded.
+func (g *Manager) AddShutdownJobWithRetry(f ShtdownJob, attempts int, backoff time.Duration) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	g.AddShutdownJob(func() error {
+		var err error
+		for i := 0; i < attempts; i++ {
+			if i > 0 {
+				if remaining, ok := g.shutdownRemaining(); ok && remaining < backoff {
+					g.log().Errorf("shutdown job retry stopped after %d attempts: deadline too close", i)
+					break
+				}
+				time.Sleep(backoff)
+			}
+			if err = f(); err == nil {
+				return nil
+			}
+		}
+		return err
+	})
+}
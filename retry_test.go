@@ -0,0 +1,58 @@
+package graceful
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAddShutdownJobWithRetry(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddShutdownJobWithRetry(func() error {
+		if atomic.AddInt32(&count, 1) < 3 {
+			return errors.New("flush failed")
+		}
+		return nil
+	}, 5, time.Millisecond)
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&count) != 3 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+	if len(m.Errors()) != 0 {
+		t.Errorf("unexpected errors: %v", m.Errors())
+	}
+}
+
+func TestAddShutdownJobWithRetryDeadline(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithShutdownTimeout(100*time.Millisecond),
+	)
+
+	m.AddShutdownJobWithRetry(func() error {
+		atomic.AddInt32(&count, 1)
+		return errors.New("flush failed")
+	}, 10, 40*time.Millisecond)
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if n := atomic.LoadInt32(&count); n < 2 || n > 3 {
+		t.Errorf("count error: %v", n)
+	}
+	if errs := m.Errors(); len(errs) != 1 || errs[0].Error() != "flush failed" {
+		t.Errorf("errors error: %v", errs)
+	}
+	if m.TimedOut() {
+		t.Error("retries should stop before the deadline")
+	}
+}
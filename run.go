@@ -0,0 +1,25 @@
+package graceful
+
+import "errors"
+
+// Run blocks until the shutdown completed and returns the errors collected
+// from the jobs joined with errors.Join, or nil. It replaces the usual
+// <-m.Done() at the end of main:
+//
+//	if err := m.Run(); err != nil {
+//		os.Exit(1)
+//	}
+func (g *Manager) Run() error {
+	<-g.Done()
+	return errors.Join(g.Errors()...)
+}
+
+// Serve runs jobs as running jobs of a new manager with the default options
+// until a signal shuts it down, see Run.
+func Serve(jobs ...RunningJob) error {
+	m := NewManager()
+	for _, job := range jobs {
+		m.AddRunningJob(job)
+	}
+	return m.Run()
+}
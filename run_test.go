@@ -0,0 +1,42 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestManagerRun(t *testing.T) {
+	setup()
+	errFlush := errors.New("flush failed")
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddShutdownJob(func() error {
+		return errFlush
+	})
+
+	m.DoGracefulShutdown()
+	if err := m.Run(); !errors.Is(err, errFlush) {
+		t.Errorf("run error: %v", err)
+	}
+}
+
+func TestServe(t *testing.T) {
+	setup()
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- Serve(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		})
+	}()
+
+	<-started
+	sendSignal(t, syscall.SIGTERM)
+	if err := <-done; err != nil {
+		t.Errorf("serve error: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package graceful
+
+import "os"
+
+// signalBufferSize is how many signals a slow subscriber can lag behind
+const signalBufferSize = 8
+
+// Signals returns a channel that receives every signal the manager handles,
+// before the manager acts on it. Signals are dropped for a subscriber whose
+// buffer is full, so a slow reader never blocks the signal handler.
+// The channel is closed once the signal handler stops, right away if it is
+// disabled with WithoutSignalHandler.
+func (g *Manager) Signals() <-chan os.Signal {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	ch := make(chan os.Signal, signalBufferSize)
+	if g.signalsClosed {
+		close(ch)
+		return ch
+	}
+	g.signalSubs = append(g.signalSubs, ch)
+	return ch
+}
+
+// PauseSignals makes the signal handler ignore incoming signals until
+// ResumeSignals is called. They are still logged, counted and forwarded to
+// Signals subscribers, but start no shutdown, reload or other action. Use it
+// for restarts you handle yourself, and keep the pause short: an orchestrator
+// sending SIGTERM while paused gets no response and kills the process once
+// its grace period ends.
+func (g *Manager) PauseSignals() {
+	g.signalsPaused.Store(true)
+}
+
+// ResumeSignals makes the signal handler act on incoming signals again.
+// Signals received while paused are not replayed.
+func (g *Manager) ResumeSignals() {
+	g.signalsPaused.Store(false)
+}
+
+// SignalCount returns how many signals the handler received so far
+func (g *Manager) SignalCount() int {
+	return int(g.signalCount.Load())
+}
+
+// SignalsSeen returns how many times each signal was received, by name.
+func (g *Manager) SignalsSeen() map[string]int {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.copySignalsSeen()
+}
+
+// copySignalsSeen copies the signal counters. Caller must hold the lock.
+func (g *Manager) copySignalsSeen() map[string]int {
+	seen := make(map[string]int, len(g.signalsSeen))
+	for name, n := range g.signalsSeen {
+		seen[name] = n
+	}
+	return seen
+}
+
+// recordSignal counts sig
+func (g *Manager) recordSignal(sig os.Signal) {
+	g.signalCount.Add(1)
+	g.lock.Lock()
+	g.signalsSeen[sig.String()]++
+	g.lock.Unlock()
+}
+
+// publishSignal forwards sig to every subscriber without blocking
+func (g *Manager) publishSignal(sig os.Signal) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	for _, ch := range g.signalSubs {
+		select {
+		case ch <- sig:
+		default:
+		}
+	}
+}
+
+// closeSignalSubs closes every subscriber channel once the handler stops
+func (g *Manager) closeSignalSubs() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for _, ch := range g.signalSubs {
+		close(ch)
+	}
+	g.signalSubs = nil
+	g.signalsClosed = true
+}
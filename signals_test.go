@@ -0,0 +1,89 @@
+package graceful
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSignals(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	<-m.Started()
+	ch := m.Signals()
+
+	sendSignal(t, syscall.SIGTERM)
+	<-m.Done()
+
+	var got []string
+	for sig := range ch {
+		got = append(got, signalName(sig))
+	}
+	if len(got) != 1 || got[0] != "SIGTERM" {
+		t.Errorf("signals error: %v", got)
+	}
+}
+
+func TestSignalsWithoutSignalHandler(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()), WithoutSignalHandler())
+
+	if _, ok := <-m.Signals(); ok {
+		t.Error("channel should be closed")
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
+
+func TestSignalCount(t *testing.T) {
+	setup()
+	m := NewManager(
+		WithLogger(NewEmptyLogger()),
+		WithSignalAction(syscall.SIGHUP, ActionIgnore),
+	)
+	<-m.Started()
+
+	for i := 1; i <= 2; i++ {
+		sendSignal(t, syscall.SIGHUP)
+		for j := 0; j < 100 && m.SignalCount() < i; j++ {
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	sendSignal(t, syscall.SIGTERM)
+	<-m.Done()
+
+	if m.SignalCount() != 3 {
+		t.Errorf("signal count error: %d", m.SignalCount())
+	}
+	seen := m.Stats().SignalsSeen
+	if seen[syscall.SIGHUP.String()] != 2 || seen[syscall.SIGTERM.String()] != 1 {
+		t.Errorf("signals seen error: %v", seen)
+	}
+}
+
+func TestPauseResumeSignals(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+	<-m.Started()
+
+	m.PauseSignals()
+	sendSignal(t, syscall.SIGTERM)
+	for i := 0; i < 100 && m.SignalCount() < 1; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	select {
+	case <-m.ShutdownContext().Done():
+		t.Fatal("paused signal should not start the shutdown")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	m.ResumeSignals()
+	sendSignal(t, syscall.SIGTERM)
+	<-m.Done()
+
+	if m.SignalCount() != 2 {
+		t.Errorf("signal count error: %d", m.SignalCount())
+	}
+}
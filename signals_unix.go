@@ -8,4 +8,10 @@ import (
 	"syscall"
 )
 
-var signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGTSTP}
+var signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGTSTP, syscall.SIGCONT}
+
+// pauseSignal and resumeSignal pause and resume the jobs by default
+var (
+	pauseSignal  os.Signal = syscall.SIGTSTP
+	resumeSignal os.Signal = syscall.SIGCONT
+)
@@ -9,3 +9,9 @@ import (
 )
 
 var signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// Windows has no job control signals, jobs are only paused with Pause
+var (
+	pauseSignal  os.Signal
+	resumeSignal os.Signal
+)
@@ -0,0 +1,208 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// SlogLoggerOption configures the slog based logger.
+type SlogLoggerOption func(*slogLoggerOptions)
+
+type slogLoggerOptions struct {
+	logger    *slog.Logger
+	writer    io.Writer
+	json      bool
+	addSource bool
+	group     string
+}
+
+// WithJSON uses the slog JSON handler.
+func WithJSON() SlogLoggerOption {
+	return func(o *slogLoggerOptions) {
+		o.json = true
+	}
+}
+
+// WithText uses the slog text handler. This is the default.
+func WithText() SlogLoggerOption {
+	return func(o *slogLoggerOptions) {
+		o.json = false
+	}
+}
+
+// WithSlogWriter sets the destination of the built-in handler. Defaults to os.Stderr.
+func WithSlogWriter(w io.Writer) SlogLoggerOption {
+	return func(o *slogLoggerOptions) {
+		o.writer = w
+	}
+}
+
+// WithSlogAddSource adds the source file and line of each log call
+// to the output of the built-in handler.
+func WithSlogAddSource() SlogLoggerOption {
+	return func(o *slogLoggerOptions) {
+		o.addSource = true
+	}
+}
+
+// WithSlogGroup puts the attributes of each log call under the named group.
+func WithSlogGroup(name string) SlogLoggerOption {
+	return func(o *slogLoggerOptions) {
+		o.group = name
+	}
+}
+
+// WithSlog uses an existing *slog.Logger and ignores the handler options.
+func WithSlog(l *slog.Logger) SlogLoggerOption {
+	return func(o *slogLoggerOptions) {
+		o.logger = l
+	}
+}
+
+// NewSlogLogger for structured logger based on log/slog.
+func NewSlogLogger(opts ...SlogLoggerOption) Logger {
+	o := &slogLoggerOptions{
+		writer: os.Stderr,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	logger := o.logger
+	if logger == nil {
+		handlerOpts := &slog.HandlerOptions{AddSource: o.addSource}
+		if o.json {
+			logger = slog.New(slog.NewJSONHandler(o.writer, handlerOpts))
+		} else {
+			logger = slog.New(slog.NewTextHandler(o.writer, handlerOpts))
+		}
+	}
+
+	if o.group != "" {
+		logger = logger.WithGroup(o.group)
+	}
+
+	return slogLogger{logger: logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// log emits a record whose source is the caller of the Logger method
+func (l slogLogger) log(ctx context.Context, level slog.Level, msg string, attrs []interface{}) {
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	// skip [runtime.Callers, log, Logger method]
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(attrs...)
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+func (l slogLogger) Infof(format string, args ...interface{}) {
+	l.log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, args...), nil)
+}
+
+// Errorf attaches a trailing error argument as the "error" attribute.
+// The error is left out of the message unless the format refers to it.
+func (l slogLogger) Errorf(format string, args ...interface{}) {
+	msg, attrs := errorfArgs(format, args)
+	l.log(context.Background(), slog.LevelError, msg, attrs)
+}
+
+func (l slogLogger) Fatalf(format string, args ...interface{}) {
+	msg, attrs := errorfArgs(format, args)
+	l.log(context.Background(), slog.LevelError, msg, attrs)
+	os.Exit(1)
+}
+
+func (l slogLogger) Info(args ...interface{}) {
+	l.log(context.Background(), slog.LevelInfo, fmt.Sprint(args...), nil)
+}
+
+func (l slogLogger) Error(args ...interface{}) {
+	msg, attrs := errorArgs(args)
+	l.log(context.Background(), slog.LevelError, msg, attrs)
+}
+
+func (l slogLogger) Fatal(args ...interface{}) {
+	msg, attrs := errorArgs(args)
+	l.log(context.Background(), slog.LevelError, msg, attrs)
+}
+
+// InfofContext passes ctx to the handler, so it can read request scoped values.
+func (l slogLogger) InfofContext(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelInfo, fmt.Sprintf(format, args...), nil)
+}
+
+// ErrorfContext passes ctx to the handler, so it can read request scoped values.
+func (l slogLogger) ErrorfContext(ctx context.Context, format string, args ...interface{}) {
+	msg, attrs := errorfArgs(format, args)
+	l.log(ctx, slog.LevelError, msg, attrs)
+}
+
+// errorfArgs builds the message and attributes for Errorf style calls
+func errorfArgs(format string, args []interface{}) (string, []interface{}) {
+	err, ok := lastError(args)
+	if !ok {
+		return fmt.Sprintf(format, args...), nil
+	}
+	// the error is left out of the message only when the format clearly has
+	// no verb for it, it is always added as the error attribute
+	if n, ok := countVerbs(format); ok && n < len(args) {
+		args = args[:len(args)-1]
+	}
+	return fmt.Sprintf(format, args...), []interface{}{slog.Any("error", err)}
+}
+
+// errorArgs builds the message and attributes for Error style calls
+func errorArgs(args []interface{}) (string, []interface{}) {
+	err, ok := lastError(args)
+	if !ok {
+		return fmt.Sprint(args...), nil
+	}
+	if len(args) > 1 {
+		args = args[:len(args)-1]
+	}
+	return fmt.Sprint(args...), []interface{}{slog.Any("error", err)}
+}
+
+func lastError(args []interface{}) (error, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	err, ok := args[len(args)-1].(error)
+	return err, ok && err != nil
+}
+
+// countVerbs returns the number of arguments format consumes, ignoring "%%".
+// ok is false when that cannot be told from the verbs alone, e.g. with a
+// "*" width or precision, an explicit "[n]" argument index or a trailing "%".
+func countVerbs(format string) (n int, ok bool) {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		// flags, width and precision
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i == len(format) || format[i] == '*' || format[i] == '[' {
+			return 0, false
+		}
+		if format[i] != '%' {
+			n++
+		}
+	}
+	return n, true
+}
@@ -0,0 +1,163 @@
+package graceful
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewSlogLogger(WithJSON(), WithSlogWriter(&buf))
+	l.Infof("hello %s", "world")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if entry["msg"] != "hello world" || entry["level"] != "INFO" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+}
+
+func TestSlogLoggerText(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewSlogLogger(WithText(), WithSlogWriter(&buf))
+	l.Info("hello")
+
+	if !strings.Contains(buf.String(), "level=INFO msg=hello") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestSlogLoggerErrorfAttr(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewSlogLogger(WithJSON(), WithSlogWriter(&buf))
+	l.Errorf("shutdown job %s failed", "flush", errors.New("disk full"))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if entry["msg"] != "shutdown job flush failed" {
+		t.Errorf("unexpected message: %v", entry["msg"])
+	}
+	if entry["error"] != "disk full" {
+		t.Errorf("unexpected error attr: %v", entry["error"])
+	}
+
+	buf.Reset()
+	l.Errorf("failed: %v", errors.New("disk full"))
+	if !strings.Contains(buf.String(), `"msg":"failed: disk full"`) {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	// a "*" width consumes an argument, the error stays in the message
+	buf.Reset()
+	l.Errorf("job %*d: %v", 3, 7, errors.New("disk full"))
+	if !strings.Contains(buf.String(), `"msg":"job   7: disk full"`) ||
+		!strings.Contains(buf.String(), `"error":"disk full"`) {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	buf.Reset()
+	l.Errorf("%[2]v in %[1]v", "flush", errors.New("disk full"))
+	if !strings.Contains(buf.String(), `"msg":"disk full in flush"`) ||
+		!strings.Contains(buf.String(), `"error":"disk full"`) {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	buf.Reset()
+	l.Errorf("count %d", 3)
+	if strings.Contains(buf.String(), `"error"`) {
+		t.Errorf("unexpected error attr: %s", buf.String())
+	}
+}
+
+func TestWithSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, nil))
+	o := newOptions(WithSlogLogger(l))
+	o.logger.Info("hello")
+
+	if !strings.Contains(buf.String(), "msg=hello") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestSlogLoggerAddSource(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewSlogLogger(WithText(), WithSlogWriter(&buf), WithSlogAddSource())
+	l.Info("hello")
+
+	if !strings.Contains(buf.String(), "source=") || !strings.Contains(buf.String(), "slog_test.go:") {
+		t.Errorf("missing caller source: %s", buf.String())
+	}
+}
+
+func TestSlogLoggerGroup(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewSlogLogger(WithJSON(), WithSlogWriter(&buf), WithSlogGroup("graceful"))
+	l.Error("failed", errors.New("disk full"))
+
+	if !strings.Contains(buf.String(), `"graceful":{"error":"disk full"}`) {
+		t.Errorf("missing group: %s", buf.String())
+	}
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	setup()
+	var buf bytes.Buffer
+	m := NewManager(WithLogger(NewSlogLogger(WithText(), WithSlogWriter(&buf))))
+
+	m.AddNamedRunningJob("worker", func(ctx context.Context) error {
+		LoggerFromContext(ctx).Info("working")
+		return nil
+	})
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if !strings.Contains(buf.String(), "msg=working job=worker") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	// no job logger in the context
+	if _, ok := LoggerFromContext(context.Background()).(defaultLogger); !ok {
+		t.Error("should fall back to the default logger")
+	}
+}
+
+// traceHandler copies the trace ID found in the context to the record
+type traceHandler struct {
+	slog.Handler
+}
+
+func (h traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id, ok := ctx.Value(ctxKey{}).(string); ok {
+		r.AddAttrs(slog.String("trace_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestSlogLoggerContext(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewSlogLogger(WithSlog(slog.New(traceHandler{slog.NewTextHandler(&buf, nil)})))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "abc")
+
+	cl, ok := l.(ContextLogger)
+	if !ok {
+		t.Fatal("slog logger should implement ContextLogger")
+	}
+	cl.InfofContext(ctx, "hello %s", "world")
+	cl.ErrorfContext(ctx, "failed", errors.New("disk full"))
+
+	out := buf.String()
+	if !strings.Contains(out, `msg="hello world" trace_id=abc`) ||
+		!strings.Contains(out, `msg=failed error="disk full" trace_id=abc`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
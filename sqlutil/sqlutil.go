@@ -0,0 +1,48 @@
+// Package sqlutil closes database/sql connection pools during a graceful shutdown.
+package sqlutil
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/appleboy/graceful"
+)
+
+// pollInterval is how often the pool is checked for in-use connections
+const pollInterval = 10 * time.Millisecond
+
+// AddDB registers a shutdown job that waits up to drain for the in-use
+// connections of db to be released, then closes it. New queries are not
+// blocked while draining, connections are only no longer kept idle once
+// released: running jobs should stop issuing queries once their context is
+// done, so in-flight queries can finish before the pool is closed. Errors
+// are recorded by the manager.
+func AddDB(m *graceful.Manager, db *sql.DB, drain time.Duration) {
+	m.AddShutdownJob(func() error {
+		return closeDB(db, drain)
+	})
+}
+
+// closeDB waits for the pool to become idle and closes it
+func closeDB(db *sql.DB, drain time.Duration) error {
+	// released connections are closed instead of kept idle while draining
+	db.SetMaxIdleConns(0)
+
+	var drainErr error
+	deadline := time.Now().Add(drain)
+	for {
+		inUse := db.Stats().InUse
+		if inUse == 0 {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			drainErr = fmt.Errorf("sqlutil: %d connections still in use after %s", inUse, drain)
+			break
+		}
+		time.Sleep(pollInterval)
+	}
+
+	return errors.Join(drainErr, db.Close())
+}
@@ -0,0 +1,98 @@
+package sqlutil
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/appleboy/graceful"
+)
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+var registerOnce sync.Once
+
+func openDB(t *testing.T) *sql.DB {
+	registerOnce.Do(func() {
+		sql.Register("sqlutil-fake", fakeDriver{})
+	})
+	db, err := sql.Open("sqlutil-fake", "")
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+	return db
+}
+
+func TestCloseDB(t *testing.T) {
+	db := openDB(t)
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin error: %v", err)
+	}
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = tx.Commit()
+	}()
+
+	if err := closeDB(db, time.Second); err != nil {
+		t.Errorf("close error: %v", err)
+	}
+	if err := db.PingContext(context.Background()); err == nil {
+		t.Error("db should be closed")
+	}
+}
+
+func TestCloseDBDrainTimeout(t *testing.T) {
+	db := openDB(t)
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin error: %v", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = closeDB(db, 30*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "1 connections still in use") {
+		t.Errorf("close error: %v", err)
+	}
+}
+
+func TestAddDB(t *testing.T) {
+	db := openDB(t)
+	replica := openDB(t)
+	m := graceful.NewIndependentManager(graceful.WithLogger(graceful.NewEmptyLogger()))
+	AddDB(m, db, time.Second)
+	AddDB(m, replica, time.Second)
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if len(m.Errors()) != 0 {
+		t.Errorf("unexpected errors: %v", m.Errors())
+	}
+	for _, db := range []*sql.DB{db, replica} {
+		if err := db.PingContext(context.Background()); err == nil {
+			t.Error("db should be closed")
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package graceful
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stats is a snapshot of the manager state
+type Stats struct {
+	// RunningJobs is the number of running jobs currently executing
+	RunningJobs int `json:"running_jobs"`
+	// ShutdownJobs is the number of registered shutdown jobs
+	ShutdownJobs int `json:"shutdown_jobs"`
+	// Errors is the number of collected errors
+	Errors int `json:"errors"`
+	// ShuttingDown is true once Drain or the shutdown started
+	ShuttingDown bool `json:"shutting_down"`
+	// Elapsed is the time since the shutdown started, zero before
+	Elapsed time.Duration `json:"elapsed"`
+	// Signals is the number of signals received
+	Signals int `json:"signals"`
+	// SignalsSeen counts the received signals by name
+	SignalsSeen map[string]int `json:"signals_seen,omitempty"`
+}
+
+// String returns a single line summary of the stats
+func (s Stats) String() string {
+	return fmt.Sprintf(
+		"running_jobs=%d shutdown_jobs=%d errors=%d shutting_down=%t elapsed=%s signals=%d",
+		s.RunningJobs, s.ShutdownJobs, s.Errors, s.ShuttingDown, s.Elapsed, s.Signals,
+	)
+}
+
+// defaultSummary is the line logged once the shutdown finished
+func defaultSummary(s Stats) string {
+	return fmt.Sprintf(
+		"shutdown complete: %d shutdown jobs, %d errors, took %s",
+		s.ShutdownJobs, s.Errors, s.Elapsed,
+	)
+}
+
+// logSummary logs the shutdown summary through the formatter
+func (g *Manager) logSummary() {
+	if g.summaryFormatter == nil {
+		return
+	}
+	s := g.Stats()
+	msg := g.summaryFormatter(s)
+	if s.Errors > 0 {
+		g.log().Error(msg)
+		return
+	}
+	g.log().Info(msg)
+}
+
+// Stats returns a snapshot of the manager state taken under the lock.
+func (g *Manager) Stats() Stats {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	s := Stats{
+		RunningJobs:  g.activeJobs,
+		ShutdownJobs: len(g.runAtShutdown),
+		Errors:       len(g.errors),
+		ShuttingDown: g.state != stateRunning,
+		Signals:      g.SignalCount(),
+	}
+	if len(g.signalsSeen) > 0 {
+		s.SignalsSeen = g.copySignalsSeen()
+	}
+	if !g.shutdownStart.IsZero() {
+		s.Elapsed = time.Since(g.shutdownStart)
+	}
+	return s
+}
@@ -0,0 +1,77 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	started := make(chan struct{})
+	m.AddRunningJob(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		return errors.New("worker error")
+	})
+	m.AddShutdownJob(func() error {
+		return nil
+	})
+	<-started
+
+	s := m.Stats()
+	if s.RunningJobs != 1 || s.ShutdownJobs != 1 || s.Errors != 0 || s.ShuttingDown || s.Elapsed != 0 {
+		t.Errorf("stats error: %v", s)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	s = m.Stats()
+	if s.RunningJobs != 0 || s.Errors != 1 || !s.ShuttingDown || s.Elapsed < 20*time.Millisecond {
+		t.Errorf("stats error: %v", s)
+	}
+	if !strings.Contains(s.String(), "running_jobs=0 shutdown_jobs=1 errors=1 shutting_down=true") {
+		t.Errorf("stats string error: %s", s)
+	}
+}
+
+func TestSummaryFormatter(t *testing.T) {
+	setup()
+	logger := NewBufferLogger(10)
+	m := NewManager(
+		WithLogger(logger),
+		WithSummaryFormatter(func(s Stats) string {
+			return fmt.Sprintf("done errors=%d", s.Errors)
+		}),
+	)
+	m.AddShutdownJob(func() error {
+		return errors.New("cleanup error")
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	found := false
+	for _, msg := range logger.Messages() {
+		if msg == "ERROR: done errors=1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("summary not logged: %v", logger.Messages())
+	}
+}
+
+func TestDefaultSummary(t *testing.T) {
+	s := defaultSummary(Stats{ShutdownJobs: 5, Errors: 2, Elapsed: 1200 * time.Millisecond})
+	if s != "shutdown complete: 5 shutdown jobs, 2 errors, took 1.2s" {
+		t.Errorf("summary error: %s", s)
+	}
+}
@@ -0,0 +1,55 @@
+package graceful
+
+import "sort"
+
+// JobInfo describes a registered job.
+type JobInfo struct {
+	// Name is the job name
+	Name string
+	// Tags are the tags the job was added with
+	Tags []string
+	// Done is true once the job returned
+	Done bool
+}
+
+// AddRunningJobTagged add named running task labelled with tags,
+// so it can be looked up with JobsByTag.
+func (g *Manager) AddRunningJobTagged(name string, f RunningJob, tags ...string) {
+	g.lock.Lock()
+	g.jobTags[name] = append([]string(nil), tags...)
+	for _, tag := range tags {
+		g.tagIndex[tag] = append(g.tagIndex[tag], name)
+	}
+	g.lock.Unlock()
+
+	g.AddNamedRunningJob(name, f)
+}
+
+// JobsByTag returns the jobs added with tag, sorted by name.
+func (g *Manager) JobsByTag(tag string) []JobInfo {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	jobs := make([]JobInfo, 0, len(g.tagIndex[tag]))
+	for _, name := range g.tagIndex[tag] {
+		info := JobInfo{
+			Name: name,
+			Tags: append([]string(nil), g.jobTags[name]...),
+		}
+		if done, ok := g.jobDone[name]; ok {
+			select {
+			case <-done:
+				info.Done = true
+			default:
+			}
+		} else {
+			// rejected jobs never run
+			info.Done = true
+		}
+		jobs = append(jobs, info)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+	return jobs
+}
@@ -0,0 +1,37 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJobsByTag(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddRunningJobTagged("http", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}, "server")
+	m.AddRunningJobTagged("queue-b", func(ctx context.Context) error {
+		return nil
+	}, "worker")
+	m.AddRunningJobTagged("queue-a", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}, "worker", "critical")
+	<-m.WaitJob("queue-b")
+
+	jobs := m.JobsByTag("worker")
+	if len(jobs) != 2 ||
+		jobs[0].Name != "queue-a" || jobs[0].Done || len(jobs[0].Tags) != 2 ||
+		jobs[1].Name != "queue-b" || !jobs[1].Done {
+		t.Errorf("jobs error: %+v", jobs)
+	}
+	if jobs := m.JobsByTag("missing"); len(jobs) != 0 {
+		t.Errorf("jobs error: %+v", jobs)
+	}
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+}
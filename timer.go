@@ -0,0 +1,97 @@
+package graceful
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// TimerOption configures a timer job.
+type TimerOption func(*timerOptions)
+
+type timerOptions struct {
+	immediate bool
+	jitter    float64
+	rand      func() float64
+}
+
+// WithRunImmediately runs the timer job once right away
+// instead of waiting for the first tick.
+func WithRunImmediately() TimerOption {
+	return func(o *timerOptions) {
+		o.immediate = true
+	}
+}
+
+// WithTimerJitter delays the first tick by a random fraction of the
+// interval, up to fraction. Use it to spread out many timers that share
+// the same interval. fraction is clamped to [0, 1].
+func WithTimerJitter(fraction float64) TimerOption {
+	return func(o *timerOptions) {
+		switch {
+		case fraction < 0:
+			fraction = 0
+		case fraction > 1:
+			fraction = 1
+		}
+		o.jitter = fraction
+	}
+}
+
+// withTimerRand replaces the jitter source, so tests get a fixed offset.
+func withTimerRand(r *rand.Rand) TimerOption {
+	return func(o *timerOptions) {
+		o.rand = r.Float64
+	}
+}
+
+// AddTimerJob add running task that calls f every interval until shutdown.
+// Errors returned by f are recorded and do not stop the timer.
+func (g *Manager) AddTimerJob(interval time.Duration, f RunningJob, opts ...TimerOption) {
+	o := &timerOptions{rand: rand.Float64}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var delay time.Duration
+	if o.jitter > 0 {
+		delay = time.Duration(o.rand() * o.jitter * float64(interval))
+	}
+
+	name := g.nextRunningJobName()
+	g.AddNamedRunningJob(name, func(ctx context.Context) error {
+		if o.immediate {
+			g.runTimerTick(ctx, name, f)
+		}
+
+		if delay > 0 {
+			t := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return nil
+			case <-t.C:
+				g.runTimerTick(ctx, name, f)
+			}
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				g.runTimerTick(ctx, name, f)
+			}
+		}
+	})
+}
+
+// runTimerTick calls f once and records its error for the named timer job
+func (g *Manager) runTimerTick(ctx context.Context, name string, f RunningJob) {
+	if err := f(ctx); err != nil {
+		g.recordRunningError(name, err)
+	}
+}
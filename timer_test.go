@@ -0,0 +1,79 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTimerJob(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddTimerJob(20*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return errors.New("tick error")
+	})
+
+	time.Sleep(70 * time.Millisecond)
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	n := atomic.LoadInt32(&count)
+	if n < 2 || n > 4 {
+		t.Errorf("count error: %v", n)
+	}
+	if len(m.errors) != int(n) {
+		t.Errorf("fail error count: %d", len(m.errors))
+	}
+}
+
+func TestTimerJobRunImmediately(t *testing.T) {
+	setup()
+	var count int32 = 0
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.AddTimerJob(time.Hour, func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}, WithRunImmediately())
+
+	time.Sleep(20 * time.Millisecond)
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("count error: %v", atomic.LoadInt32(&count))
+	}
+}
+
+func TestTimerJobJitter(t *testing.T) {
+	setup()
+	var first atomic.Value
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	start := time.Now()
+	m.AddTimerJob(200*time.Millisecond, func(ctx context.Context) error {
+		if first.Load() == nil {
+			first.Store(time.Since(start))
+		}
+		return nil
+	}, WithTimerJitter(0.25), withTimerRand(rand.New(rand.NewSource(1))))
+
+	time.Sleep(150 * time.Millisecond)
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	// rand.NewSource(1) yields 0.604..., so the first tick is at ~30ms
+	d, ok := first.Load().(time.Duration)
+	if !ok {
+		t.Fatal("timer should tick before the interval")
+	}
+	if d < 25*time.Millisecond || d > 120*time.Millisecond {
+		t.Errorf("first tick error: %v", d)
+	}
+}
@@ -0,0 +1,74 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// TriggerKind is what started the shutdown.
+type TriggerKind int
+
+const (
+	// TriggerNone means the shutdown has not started.
+	TriggerNone TriggerKind = iota
+	// TriggerSignal is an OS signal.
+	TriggerSignal
+	// TriggerContext is the background context being closed.
+	TriggerContext
+	// TriggerExplicit is a call such as DoGracefulShutdown or Drain, the
+	// trigger channel, a TriggerFrom watcher, a failing group or a panic
+	// with WithShutdownOnPanic.
+	TriggerExplicit
+	// TriggerTimeout is the ShutdownAfter delay elapsing.
+	TriggerTimeout
+)
+
+// String returns the trigger kind name
+func (k TriggerKind) String() string {
+	switch k {
+	case TriggerNone:
+		return "none"
+	case TriggerSignal:
+		return "signal"
+	case TriggerContext:
+		return "context"
+	case TriggerExplicit:
+		return "explicit"
+	case TriggerTimeout:
+		return "timeout"
+	}
+	return "unknown"
+}
+
+// TriggerSource tells why the process shut down, for audit logs.
+type TriggerSource struct {
+	Kind TriggerKind
+	// Signal is the received signal when Kind is TriggerSignal
+	Signal os.Signal
+}
+
+// String returns the kind, followed by the signal name for signals
+func (s TriggerSource) String() string {
+	if s.Kind == TriggerSignal && s.Signal != nil {
+		return s.Kind.String() + " " + signalName(s.Signal)
+	}
+	return s.Kind.String()
+}
+
+// Trigger returns what started the shutdown, derived from ShutdownCause.
+func (g *Manager) Trigger() TriggerSource {
+	cause := context.Cause(g.shutdownCtx)
+	var sigErr *SignalError
+	switch {
+	case cause == nil:
+		return TriggerSource{Kind: TriggerNone}
+	case errors.As(cause, &sigErr):
+		return TriggerSource{Kind: TriggerSignal, Signal: sigErr.Signal}
+	case errors.Is(cause, ErrParentContextDone):
+		return TriggerSource{Kind: TriggerContext}
+	case errors.Is(cause, ErrShutdownAfter):
+		return TriggerSource{Kind: TriggerTimeout}
+	}
+	return TriggerSource{Kind: TriggerExplicit}
+}
@@ -0,0 +1,79 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTrigger(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		start func(m *Manager, cancel context.CancelFunc)
+		want  string
+	}{
+		{"explicit", func(m *Manager, _ context.CancelFunc) { m.DoGracefulShutdown() }, "explicit"},
+		{"context", func(_ *Manager, cancel context.CancelFunc) { cancel() }, "context"},
+		{"timeout", func(m *Manager, _ context.CancelFunc) { m.ShutdownAfter(time.Millisecond) }, "timeout"},
+		{"signal", func(m *Manager, _ context.CancelFunc) { sendSignal(t, syscall.SIGTERM) }, "signal SIGTERM"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setup()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			m := NewManager(WithContext(ctx), WithLogger(NewEmptyLogger()))
+			<-m.Started()
+			if m.Trigger().Kind != TriggerNone {
+				t.Errorf("trigger error: %v", m.Trigger())
+			}
+
+			tc.start(m, cancel)
+			<-m.Done()
+
+			if got := m.Trigger().String(); got != tc.want {
+				t.Errorf("trigger error: %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTriggerFrom(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	notice := make(chan struct{})
+	m.TriggerFrom(context.Background(), func(ctx context.Context) error {
+		select {
+		case <-notice:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	close(notice)
+	<-m.Done()
+
+	if !errors.Is(m.ShutdownCause(), ErrExternalTrigger) {
+		t.Errorf("shutdown cause error: %v", m.ShutdownCause())
+	}
+}
+
+func TestTriggerFromStopsOnShutdown(t *testing.T) {
+	setup()
+	m := NewManager(WithLogger(NewEmptyLogger()))
+
+	m.TriggerFrom(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	m.DoGracefulShutdown()
+	<-m.Done()
+
+	if len(m.Errors()) != 0 || !errors.Is(m.ShutdownCause(), ErrShutdownRequested) {
+		t.Errorf("unexpected result: %v %v", m.Errors(), m.ShutdownCause())
+	}
+}